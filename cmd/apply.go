@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ironcore-dev/dpservice-cli/diff"
+	"github.com/ironcore-dev/dpservice-cli/dpdk/client/dynamic"
+	"github.com/ironcore-dev/dpservice-cli/dpdk/runtime"
+	"github.com/ironcore-dev/dpservice-cli/sources"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func Apply(factory DPDKClientFactory) *cobra.Command {
+	rendererOptions := &RendererOptions{Output: "name"}
+	sourcesOptions := &SourcesOptions{}
+	applyOptions := &ApplyOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "apply <-f>",
+		Short:   "Applies objects from files, creating missing ones and replacing changed ones",
+		Long:    "Applies objects from files. Missing objects are created, objects whose spec differs from the live state are deleted and created again, as dpservice does not support updates.",
+		Example: "dpservice-cli apply -f /tmp/interfaces.yaml",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunApply(cmd.Context(), factory, rendererOptions, sourcesOptions, cmd.ErrOrStderr(), *applyOptions)
+		},
+	}
+
+	rendererOptions.AddFlags(cmd.Flags())
+
+	sourcesOptions.AddFlags(cmd.Flags())
+	applyOptions.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+type ApplyOptions struct {
+	PruneOptions
+}
+
+func (o *ApplyOptions) AddFlags(fs *pflag.FlagSet) {
+	o.PruneOptions.AddFlags(fs)
+}
+
+func RunApply(
+	ctx context.Context,
+	dpdkClientFactory DPDKClientFactory,
+	rendererFactory RendererFactory,
+	sourcesReaderFactory SourcesReaderFactory,
+	errOut io.Writer,
+	opts ApplyOptions,
+) error {
+	if err := opts.PruneOptions.Validate(); err != nil {
+		return err
+	}
+
+	client, cleanup, err := dpdkClientFactory.NewClient(ctx)
+	if err != nil {
+		return fmt.Errorf("error creating dpdk client: %w", err)
+	}
+	defer DpdkClose(cleanup)
+
+	dc := dynamic.NewFromStructured(client)
+
+	iterator, err := sourcesReaderFactory.NewIterator()
+	if err != nil {
+		return fmt.Errorf("error creating sources iterator: %w", err)
+	}
+
+	objs, rawSpecs, err := sources.CollectObjectsWithRawSpecs(iterator, runtime.DefaultScheme)
+	if err != nil {
+		return fmt.Errorf("error collecting objects: %w", err)
+	}
+
+	if err := runtime.DefaultScheme.SortForCreation(objs); err != nil {
+		return fmt.Errorf("error sorting objects: %w", err)
+	}
+
+	summary := &BulkSummary{Operation: "apply"}
+	for _, obj := range objs {
+		res, operation, err := applyObject(ctx, dc, obj, rawSpecs[obj])
+		summary.add(bulkResult{Obj: obj, Res: res, Err: err})
+		if err != nil {
+			fmt.Fprintf(errOut, "Error applying %T %s: %v\n", obj, objectKey(obj), err)
+			continue
+		}
+
+		renderer, err := rendererFactory.NewRenderer(operation, os.Stdout)
+		if err != nil {
+			return fmt.Errorf("error creating renderer: %w", err)
+		}
+		if err := renderer.Render(res); err != nil {
+			return fmt.Errorf("error rendering %T: %w", obj, err)
+		}
+	}
+
+	if opts.Prune && summary.Failed == 0 {
+		if err := RunPrune(ctx, client, rendererFactory, errOut, objs, opts.PruneOptions); err != nil {
+			return err
+		}
+	}
+
+	if err := summary.Write(errOut, "text"); err != nil {
+		return fmt.Errorf("error writing summary: %w", err)
+	}
+	return summary.Err()
+}
+
+// applyObject converges a single object and returns the resulting object
+// together with the operation that was performed (created, unchanged or replaced).
+// rawSpec is the spec of obj as read from its source.
+func applyObject(ctx context.Context, dc dynamic.Client, obj, rawSpec any) (any, string, error) {
+	key, err := dynamic.ObjectKeyFromObject(obj)
+	if err != nil {
+		return nil, "", err
+	}
+
+	live, err := dc.Get(ctx, key)
+	if err != nil {
+		if !dynamic.IsNotFound(err) {
+			return nil, "", fmt.Errorf("error getting live object: %w", err)
+		}
+
+		res, err := dc.Create(ctx, obj)
+		if err != nil {
+			return nil, "", fmt.Errorf("error creating: %w", err)
+		}
+		return res, "created", nil
+	}
+
+	changes, err := diff.SourceSpec(obj, live, rawSpec)
+	if err != nil {
+		return nil, "", fmt.Errorf("error comparing with live object: %w", err)
+	}
+	if len(changes) == 0 {
+		return live, "unchanged", nil
+	}
+
+	if _, err := dc.Delete(ctx, obj); err != nil {
+		return nil, "", fmt.Errorf("error deleting for replacement: %w", err)
+	}
+	res, err := dc.Create(ctx, obj)
+	if err != nil {
+		// the live object is gone now, so this has to be told apart from a plain create error
+		return nil, "", fmt.Errorf("deleted but re-create failed: %w", err)
+	}
+	return res, "replaced", nil
+}
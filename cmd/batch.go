@@ -0,0 +1,211 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	apierrors "github.com/ironcore-dev/dpservice-go/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// BatchSummary is the outcome of running a batch script.
+type BatchSummary struct {
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Skipped   int               `json:"skipped"`
+	Lines     []BatchLineResult `json:"lines"`
+}
+
+// BatchLineResult is the outcome of a single line of a batch script.
+type BatchLineResult struct {
+	Line    int    `json:"line"`
+	Command string `json:"command"`
+	Result  string `json:"result"`
+	Message string `json:"message,omitempty"`
+}
+
+func (s *BatchSummary) add(r BatchLineResult) {
+	switch r.Result {
+	case resultSucceeded:
+		s.Succeeded++
+	case resultFailed:
+		s.Failed++
+	case resultSkipped:
+		s.Skipped++
+	}
+	s.Lines = append(s.Lines, r)
+}
+
+// Err returns an error if any line failed, so the command exits non-zero.
+func (s *BatchSummary) Err() error {
+	if s.Failed == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d lines failed", s.Failed, len(s.Lines))
+}
+
+// Write writes the result of every line and the counts in the given format.
+func (s *BatchSummary) Write(w io.Writer, format string) error {
+	switch format {
+	case "json":
+		return json.NewEncoder(w).Encode(s)
+	default:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "LINE\tRESULT\tCOMMAND\tMESSAGE")
+		for _, line := range s.Lines {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", line.Line, line.Result, line.Command, line.Message)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(w, "batch: %d succeeded, %d failed, %d skipped\n", s.Succeeded, s.Failed, s.Skipped)
+		return err
+	}
+}
+
+func Batch(dpdkClientFactory DPDKClientFactory) *cobra.Command {
+	var (
+		opts BatchOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "batch [-f script|-]",
+		Short: "Run the commands of a script on a single connection to dpservice",
+		Long: `Run the commands of a script on a single connection to dpservice. Every line is run like the arguments of
+dpservice-cli, e.g. 'create prefix --interface-id=vm1 --prefix=10.0.10.0/24'. Empty lines and lines starting with #
+are ignored. The global connection flags cannot be changed in the script.
+
+The script is read from stdin if no file or - is given. By default, the remaining lines are skipped after the
+first failing line. At the end, the result of every line is printed with its line number.`,
+		Example: "dpservice-cli batch -f setup.txt --continue-on-error\necho 'list interfaces' | dpservice-cli batch",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			in := cmd.InOrStdin()
+			if opts.Filename != "-" {
+				f, err := os.Open(opts.Filename)
+				if err != nil {
+					return fmt.Errorf("error opening script: %w", err)
+				}
+				defer f.Close()
+				in = f
+			}
+
+			return RunBatch(
+				cmd.Context(),
+				dpdkClientFactory,
+				in,
+				cmd.OutOrStdout(),
+				opts,
+			)
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+type BatchOptions struct {
+	Filename        string
+	ContinueOnError bool
+	SummaryOutput   string
+}
+
+func (o *BatchOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&o.Filename, "filename", "f", "-", "Script to run, - for stdin.")
+	fs.BoolVar(&o.ContinueOnError, "continue-on-error", o.ContinueOnError, "Run the remaining lines after a line failed.")
+	fs.StringVar(&o.SummaryOutput, "summary", "text", "Output format of the summary printed at the end. [text|json]")
+}
+
+type batchLine struct {
+	number  int
+	command string
+	args    []string
+}
+
+// readBatchScript reads the commands of a script. All lines are parsed before running any of them,
+// so a syntax error does not leave a script half done.
+func readBatchScript(in io.Reader) ([]batchLine, error) {
+	var lines []batchLine
+	scanner := bufio.NewScanner(in)
+	for number := 1; scanner.Scan(); number++ {
+		args, err := splitShellLine(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("error parsing line %d: %w", number, err)
+		}
+		if len(args) == 0 {
+			continue
+		}
+		lines = append(lines, batchLine{number: number, command: strings.TrimSpace(scanner.Text()), args: args})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading script: %w", err)
+	}
+	return lines, nil
+}
+
+func RunBatch(
+	ctx context.Context,
+	dpdkClientFactory DPDKClientFactory,
+	in io.Reader,
+	w io.Writer,
+	opts BatchOptions,
+) error {
+	switch opts.SummaryOutput {
+	case "text", "json":
+	default:
+		return fmt.Errorf("unsupported summary format %q", opts.SummaryOutput)
+	}
+
+	lines, err := readBatchScript(in)
+	if err != nil {
+		return err
+	}
+
+	factory := &sharedClientFactory{factory: dpdkClientFactory}
+	defer DpdkClose(factory.Close)
+
+	summary := &BatchSummary{}
+	failed := false
+	for _, line := range lines {
+		result := BatchLineResult{Line: line.number, Command: line.command}
+		if failed && !opts.ContinueOnError {
+			result.Result = resultSkipped
+			summary.add(result)
+			continue
+		}
+
+		root := newLineCommand(factory)
+		root.SetArgs(line.args)
+		root.SetOut(w)
+		root.SetErr(w)
+		if err := root.ExecuteContext(ctx); err != nil {
+			failed = true
+			result.Result = resultFailed
+			result.Message = err.Error()
+			// the command already rendered the error of dpservice
+			if result.Message == strconv.Itoa(apierrors.SERVER_ERROR) {
+				result.Message = "dpservice returned an error"
+			}
+		} else {
+			result.Result = resultSucceeded
+		}
+		summary.add(result)
+	}
+
+	if err := summary.Write(w, opts.SummaryOutput); err != nil {
+		return err
+	}
+	return summary.Err()
+}
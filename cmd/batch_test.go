@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+
+	. "github.com/ironcore-dev/dpservice-cli/cmd"
+	"github.com/ironcore-dev/dpservice-cli/fake"
+	"github.com/ironcore-dev/dpservice-go/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const batchScript = `# set up vm1
+create interface --id=vm1 --vni=100 --ipv4=10.0.0.1 --device=net_tap1
+
+create prefix --interface-id=vm2 --prefix=10.0.1.0/24
+create prefix --interface-id=vm1 --prefix=10.0.2.0/24
+`
+
+var _ = Describe("Batch", func() {
+	var (
+		ctx     = context.Background()
+		factory *countingFactory
+		c       client.Client
+	)
+
+	BeforeEach(func() {
+		server := fake.NewServer()
+		DeferCleanup(server.Stop)
+		factory = &countingFactory{DPDKClientFactory: server}
+
+		var (
+			cleanup func() error
+			err     error
+		)
+		c, cleanup, err = server.NewClient(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(cleanup)
+	})
+
+	runBatch := func(script string, opts BatchOptions) (BatchSummary, error) {
+		opts.SummaryOutput = "json"
+		var out bytes.Buffer
+		err := RunBatch(ctx, factory, strings.NewReader(script), &out, opts)
+
+		// the summary is the last line of the output
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		var summary BatchSummary
+		Expect(json.Unmarshal([]byte(lines[len(lines)-1]), &summary)).To(Succeed())
+		return summary, err
+	}
+
+	It("should skip the remaining lines after a failure", func() {
+		summary, err := runBatch(batchScript, BatchOptions{})
+		Expect(err).To(MatchError("1 of 3 lines failed"))
+		Expect(summary.Lines).To(Equal([]BatchLineResult{
+			{Line: 2, Command: "create interface --id=vm1 --vni=100 --ipv4=10.0.0.1 --device=net_tap1", Result: "succeeded"},
+			{Line: 4, Command: "create prefix --interface-id=vm2 --prefix=10.0.1.0/24", Result: "failed", Message: "dpservice returned an error"},
+			{Line: 5, Command: "create prefix --interface-id=vm1 --prefix=10.0.2.0/24", Result: "skipped"},
+		}))
+		Expect(factory.clients.Load()).To(Equal(int32(1)))
+	})
+
+	It("should run the remaining lines with --continue-on-error", func() {
+		summary, err := runBatch(batchScript, BatchOptions{ContinueOnError: true})
+		Expect(err).To(HaveOccurred())
+		Expect(summary.Succeeded).To(Equal(2))
+		Expect(summary.Failed).To(Equal(1))
+
+		prefixes, err := c.ListPrefixes(ctx, "vm1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(prefixes.Items).To(HaveLen(1))
+	})
+
+	It("should not run a script with syntax errors", func() {
+		var out bytes.Buffer
+		err := RunBatch(ctx, factory, strings.NewReader(batchScript+"get interface --id='vm1\n"), &out, BatchOptions{SummaryOutput: "text"})
+		Expect(err).To(MatchError(ContainSubstring("error parsing line 6")))
+		Expect(factory.clients.Load()).To(BeZero())
+	})
+
+	It("should print the result of every line", func() {
+		var out bytes.Buffer
+		Expect(RunBatch(ctx, factory, strings.NewReader("version\nget nothing\n"), &out, BatchOptions{SummaryOutput: "text", ContinueOnError: true})).
+			To(MatchError("1 of 2 lines failed"))
+		Expect(out.String()).To(MatchRegexp(`LINE\s+RESULT\s+COMMAND\s+MESSAGE\n1\s+succeeded\s+version\s*\n2\s+failed\s+get nothing\s+\S+`))
+		Expect(out.String()).To(HaveSuffix("batch: 1 succeeded, 1 failed, 0 skipped\n"))
+	})
+})
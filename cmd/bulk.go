@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/spf13/pflag"
+)
+
+type BulkOptions struct {
+	Parallelism   int
+	SummaryOutput string
+}
+
+func (o *BulkOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&o.Parallelism, "parallelism", 1, "Number of objects to process concurrently. Objects depending on each other are never processed concurrently.")
+	fs.StringVar(&o.SummaryOutput, "summary", "text", "Output format of the summary printed at the end. [text|json]")
+}
+
+func (o *BulkOptions) Validate() error {
+	if o.Parallelism < 1 {
+		return fmt.Errorf("parallelism must be at least 1, got %d", o.Parallelism)
+	}
+	switch o.SummaryOutput {
+	case "text", "json":
+	default:
+		return fmt.Errorf("unsupported summary format %q", o.SummaryOutput)
+	}
+	return nil
+}
+
+type bulkResult struct {
+	Obj     any
+	Res     any
+	Err     error
+	Skipped bool
+}
+
+// runBulk calls f for every object, level by level, with up to parallelism concurrent calls within a level.
+// After each level, handle is called with the results of that level in input order.
+// If stopOnError is set, no further object is started once a call failed and the objects
+// not started are passed to handle as skipped. The same happens once ctx is done, in which
+// case runBulk returns the error of ctx.
+func runBulk(
+	ctx context.Context,
+	levels [][]any,
+	parallelism int,
+	stopOnError bool,
+	f func(ctx context.Context, obj any) (any, error),
+	handle func(results []bulkResult) error,
+) error {
+	for l, level := range levels {
+		results := make([]bulkResult, len(level))
+
+		var (
+			wg     sync.WaitGroup
+			mu     sync.Mutex
+			failed bool
+		)
+		sem := make(chan struct{}, parallelism)
+		for i, obj := range level {
+			// wait for a free worker first, so a failure of the previous call is seen
+			sem <- struct{}{}
+			mu.Lock()
+			stop := stopOnError && failed
+			mu.Unlock()
+			if stop || ctx.Err() != nil {
+				<-sem
+				results[i] = bulkResult{Obj: obj, Skipped: true}
+				continue
+			}
+
+			wg.Add(1)
+			go func(i int, obj any) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				res, err := f(ctx, obj)
+				results[i] = bulkResult{Obj: obj, Res: res, Err: err}
+				if err != nil {
+					mu.Lock()
+					failed = true
+					mu.Unlock()
+				}
+			}(i, obj)
+		}
+		wg.Wait()
+
+		if err := handle(results); err != nil {
+			return err
+		}
+
+		if ctx.Err() != nil {
+			if err := handle(skippedResults(levels[l+1:])); err != nil {
+				return err
+			}
+			return ctx.Err()
+		}
+		if stopOnError && failed {
+			return handle(skippedResults(levels[l+1:]))
+		}
+	}
+	return nil
+}
+
+func skippedResults(levels [][]any) []bulkResult {
+	var results []bulkResult
+	for _, level := range levels {
+		for _, obj := range level {
+			results = append(results, bulkResult{Obj: obj, Skipped: true})
+		}
+	}
+	return results
+}
@@ -11,6 +11,7 @@ import (
 func Command() *cobra.Command {
 	dpdkClientOptions := &DPDKClientOptions{}
 	rendererOptions := &RendererOptions{}
+	configOptions := &ConfigOptions{}
 
 	cmd := &cobra.Command{
 		Use:           "dpservice-cli [command]",
@@ -19,21 +20,51 @@ func Command() *cobra.Command {
 		SilenceErrors: true,
 		RunE:          SubcommandRequired,
 		Version:       util.BuildVersion,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return configOptions.Apply(cmd.Root().PersistentFlags(), cmd.Flags())
+		},
 	}
 
 	rendererOptions.AddFlags(cmd.PersistentFlags())
 	dpdkClientOptions.AddFlags(cmd.PersistentFlags())
+	configOptions.AddFlags(cmd.PersistentFlags())
 
+	cmd.AddCommand(dpserviceCommands(dpdkClientOptions, rendererOptions)...)
 	cmd.AddCommand(
-		Create(dpdkClientOptions),
-		Get(dpdkClientOptions),
-		List(dpdkClientOptions),
-		Delete(dpdkClientOptions),
-		Reset(dpdkClientOptions),
-		Init(dpdkClientOptions, rendererOptions),
-		Capture(dpdkClientOptions),
+		Config(configOptions, rendererOptions),
+		FakeServer(),
+		Shell(dpdkClientOptions),
+		Batch(dpdkClientOptions),
 		completionCmd,
 	)
 
+	completions := &liveCompletions{
+		factory: func(cmd *cobra.Command) DPDKClientFactory {
+			// completions do not run PersistentPreRunE
+			_ = configOptions.Apply(cmd.Root().PersistentFlags(), cmd.Flags())
+			return dpdkClientOptions.forCompletion()
+		},
+	}
+	completions.register(cmd)
+
 	return cmd
 }
+
+// dpserviceCommands returns the commands working with dpservice, which can also be run in the shell.
+func dpserviceCommands(dpdkClientFactory DPDKClientFactory, rendererOptions *RendererOptions) []*cobra.Command {
+	return []*cobra.Command{
+		Create(dpdkClientFactory),
+		Apply(dpdkClientFactory),
+		Diff(dpdkClientFactory),
+		Export(dpdkClientFactory),
+		Get(dpdkClientFactory),
+		List(dpdkClientFactory),
+		Delete(dpdkClientFactory),
+		Wait(dpdkClientFactory),
+		Reset(dpdkClientFactory),
+		Init(dpdkClientFactory, rendererOptions),
+		Capture(dpdkClientFactory),
+		Doctor(dpdkClientFactory, rendererOptions),
+		Version(dpdkClientFactory, rendererOptions),
+	}
+}
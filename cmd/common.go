@@ -5,10 +5,13 @@ package cmd
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
 	"net/netip"
+	"os"
 	"strconv"
 	"time"
 
@@ -21,6 +24,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
@@ -31,29 +35,175 @@ type DPDKClientFactory interface {
 type DPDKClientOptions struct {
 	Address        string
 	ConnectTimeout time.Duration
+	RequestTimeout time.Duration
+	Retries        int
+	RetryBackoff   time.Duration
+	Verbosity      int
+	CheckVersion   bool
+	StrictVersion  bool
+	Record         string
+	Replay         string
+
+	TLS                bool
+	CAFile             string
+	CertFile           string
+	KeyFile            string
+	ServerName         string
+	InsecureSkipVerify bool
+
+	recorder *recorder
+	replayer *replayer
 }
 
 func (o *DPDKClientOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.Address, "address", "localhost:1337", "dpservice address.")
 	fs.DurationVar(&o.ConnectTimeout, "connect-timeout", 4*time.Second, "Timeout to connect to the dpservice.")
+	fs.DurationVar(&o.RequestTimeout, "request-timeout", o.RequestTimeout, "Timeout of a single call to the dpservice. 0 means no timeout.")
+	fs.IntVar(&o.Retries, "retries", o.Retries, "Number of times to retry calls failing with a transient error.")
+	fs.DurationVar(&o.RetryBackoff, "retry-backoff", 500*time.Millisecond, "Time to wait before the first retry, doubled for every further retry.")
+	fs.IntVar(&o.Verbosity, "v", o.Verbosity, fmt.Sprintf("Log verbosity. From %d on, every call to dpservice is logged to stderr.", TraceVerbosity))
+	fs.BoolVar(&o.CheckVersion, "check-version", o.CheckVersion, "Warn if the protocol version of dpservice is not compatible with dpservice-cli.")
+	fs.BoolVar(&o.StrictVersion, "strict-version", o.StrictVersion, "Fail if the protocol version of dpservice is not compatible with dpservice-cli. Implies --check-version.")
+	fs.StringVar(&o.Record, "record", o.Record, "Write every call to dpservice with its request and response to the file, to replay it with --replay.")
+	fs.StringVar(&o.Replay, "replay", o.Replay, "Answer calls from a file written with --record instead of connecting to dpservice.")
+	fs.BoolVar(&o.TLS, "tls", o.TLS, "Connect to dpservice using TLS. Implied by the other TLS flags.")
+	fs.StringVar(&o.CAFile, "ca-file", o.CAFile, "CA certificate file to verify the server certificate. Defaults to the system CAs.")
+	fs.StringVar(&o.CertFile, "cert-file", o.CertFile, "Client certificate file for mutual TLS.")
+	fs.StringVar(&o.KeyFile, "key-file", o.KeyFile, "Client key file for mutual TLS.")
+	fs.StringVar(&o.ServerName, "server-name", o.ServerName, "Server name to verify the server certificate against. Defaults to the host of --address.")
+	fs.BoolVar(&o.InsecureSkipVerify, "insecure-skip-verify", o.InsecureSkipVerify, "Do not verify the server certificate. Insecure, for testing only.")
 }
 
-func (o *DPDKClientOptions) NewClient(ctx context.Context) (client.Client, func() error, error) {
-	ctx, cancel := context.WithTimeout(ctx, o.ConnectTimeout)
-	defer cancel()
+func (o *DPDKClientOptions) tlsEnabled() bool {
+	return o.TLS || o.CAFile != "" || o.CertFile != "" || o.KeyFile != "" || o.ServerName != "" || o.InsecureSkipVerify
+}
+
+// TransportCredentials returns TLS credentials if any TLS flag is set and insecure credentials otherwise.
+func (o *DPDKClientOptions) TransportCredentials() (credentials.TransportCredentials, error) {
+	if !o.tlsEnabled() {
+		return insecure.NewCredentials(), nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         o.ServerName,
+		InsecureSkipVerify: o.InsecureSkipVerify,
+	}
+
+	if o.CAFile != "" {
+		data, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading ca file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in ca file %s", o.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
 
-	conn, err := grpc.DialContext(ctx, o.Address, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	if o.CertFile != "" || o.KeyFile != "" {
+		if o.CertFile == "" || o.KeyFile == "" {
+			return nil, fmt.Errorf("--cert-file and --key-file have to be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+func (o *DPDKClientOptions) NewClient(ctx context.Context) (client.Client, func() error, error) {
+	conn, err := o.Dial(ctx)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error connecting to %s: %w", o.Address, err)
+		return nil, nil, err
 	}
 
 	protoClient := dpdkproto.NewDPDKironcoreClient(conn)
 	c := client.NewClient(protoClient)
 
+	if o.CheckVersion || o.StrictVersion {
+		if err := o.checkVersion(ctx, c); err != nil {
+			_ = conn.Close()
+			return nil, nil, err
+		}
+	}
+
 	cleanup := conn.Close
 	return c, cleanup, nil
 }
 
+// Dial connects to dpservice, waiting at most --connect-timeout for the connection to be ready.
+func (o *DPDKClientOptions) Dial(ctx context.Context) (*grpc.ClientConn, error) {
+	if o.Retries < 0 {
+		return nil, fmt.Errorf("retries must not be negative, got %d", o.Retries)
+	}
+	if o.Replay != "" {
+		if o.Record != "" {
+			return nil, fmt.Errorf("--record and --replay cannot be used together")
+		}
+		return o.dialReplay(ctx)
+	}
+	creds, err := o.TransportCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.ConnectTimeout)
+	defer cancel()
+
+	var interceptors []grpc.UnaryClientInterceptor
+	if o.Record != "" {
+		if o.recorder == nil {
+			o.recorder = &recorder{filename: o.Record}
+		}
+		interceptors = append(interceptors, o.recorder.interceptor())
+	}
+	interceptors = append(interceptors, o.unaryInterceptor())
+	if o.Verbosity >= TraceVerbosity {
+		interceptors = append(interceptors, traceInterceptor(os.Stderr))
+	}
+
+	conn, err := grpc.DialContext(ctx, o.Address,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithBlock(),
+		grpc.WithChainUnaryInterceptor(interceptors...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to %s: %w", o.Address, err)
+	}
+	return conn, nil
+}
+
+// dialReplay returns a connection answering all calls from the --replay file. The recording is
+// loaded once, so calls of several connections are not answered twice.
+func (o *DPDKClientOptions) dialReplay(ctx context.Context) (*grpc.ClientConn, error) {
+	if o.replayer == nil {
+		r, err := loadReplayer(o.Replay)
+		if err != nil {
+			return nil, err
+		}
+		o.replayer = r
+	}
+
+	var interceptors []grpc.UnaryClientInterceptor
+	if o.Verbosity >= TraceVerbosity {
+		interceptors = append(interceptors, traceInterceptor(os.Stderr))
+	}
+	interceptors = append(interceptors, o.replayer.interceptor())
+	conn, err := grpc.DialContext(ctx, "passthrough:///replay",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(interceptors...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error replaying %s: %w", o.Replay, err)
+	}
+	return conn, nil
+}
+
 func DpdkClose(cleanup func() error) {
 	if err := cleanup(); err != nil {
 		fmt.Printf("error cleaning up client: %s", err)
@@ -0,0 +1,346 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	. "github.com/ironcore-dev/dpservice-cli/cmd"
+	"github.com/ironcore-dev/dpservice-go/api"
+	"github.com/ironcore-dev/dpservice-go/client"
+	dpdkproto "github.com/ironcore-dev/dpservice-go/proto"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// initializedServer is a stand-in for dpservice that only answers CheckInitialized.
+type initializedServer struct {
+	dpdkproto.UnimplementedDPDKironcoreServer
+}
+
+func (initializedServer) CheckInitialized(context.Context, *dpdkproto.CheckInitializedRequest) (*dpdkproto.CheckInitializedResponse, error) {
+	return &dpdkproto.CheckInitializedResponse{Uuid: "test-uuid"}, nil
+}
+
+// flakyServer is a stand-in for dpservice failing the first failures calls as unavailable.
+type flakyServer struct {
+	dpdkproto.UnimplementedDPDKironcoreServer
+	failures int32
+	delay    time.Duration
+	calls    atomic.Int32
+}
+
+func (s *flakyServer) call(ctx context.Context) error {
+	if s.calls.Add(1) <= s.failures {
+		return status.Error(codes.Unavailable, "dpservice is restarting")
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(s.delay):
+		return nil
+	}
+}
+
+func (s *flakyServer) ListInterfaces(ctx context.Context, _ *dpdkproto.ListInterfacesRequest) (*dpdkproto.ListInterfacesResponse, error) {
+	if err := s.call(ctx); err != nil {
+		return nil, err
+	}
+	return &dpdkproto.ListInterfacesResponse{Status: &dpdkproto.Status{}}, nil
+}
+
+func (s *flakyServer) CreateInterface(ctx context.Context, _ *dpdkproto.CreateInterfaceRequest) (*dpdkproto.CreateInterfaceResponse, error) {
+	if err := s.call(ctx); err != nil {
+		return nil, err
+	}
+	return &dpdkproto.CreateInterfaceResponse{Status: &dpdkproto.Status{}}, nil
+}
+
+type certFiles struct {
+	certFile string
+	keyFile  string
+}
+
+// writeCert writes a certificate for dnsName to dir, signed by parent or self-signed if parent is nil.
+func writeCert(dir, name, dnsName string, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (certFiles, *x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if dnsName != "" {
+		template.DNSNames = []string{dnsName}
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).NotTo(HaveOccurred())
+
+	files := certFiles{
+		certFile: filepath.Join(dir, name+".crt"),
+		keyFile:  filepath.Join(dir, name+".key"),
+	}
+	Expect(os.WriteFile(files.certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)).To(Succeed())
+	Expect(os.WriteFile(files.keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)).To(Succeed())
+	return files, cert, key
+}
+
+var _ = Describe("DPDKClientOptions", func() {
+	Context("TLS", func() {
+		var (
+			ca, server, client certFiles
+			caCert             *x509.Certificate
+			address            string
+		)
+
+		// startServer starts a TLS stand-in for dpservice, requiring client certificates if mutual is set.
+		startServer := func(mutual bool) {
+			cert, err := tls.LoadX509KeyPair(server.certFile, server.keyFile)
+			Expect(err).NotTo(HaveOccurred())
+			tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}}
+			if mutual {
+				pool := x509.NewCertPool()
+				pool.AddCert(caCert)
+				tlsConfig.ClientCAs = pool
+				tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			address = lis.Addr().String()
+
+			srv := grpc.NewServer(grpc.Creds(credentials.NewTLS(tlsConfig)))
+			dpdkproto.RegisterDPDKironcoreServer(srv, initializedServer{})
+			go func() {
+				defer GinkgoRecover()
+				_ = srv.Serve(lis)
+			}()
+			DeferCleanup(srv.Stop)
+		}
+
+		checkInitialized := func(opts *DPDKClientOptions) error {
+			opts.Address = address
+			if opts.ConnectTimeout == 0 {
+				opts.ConnectTimeout = 2 * time.Second
+			}
+			c, cleanup, err := opts.NewClient(context.Background())
+			if err != nil {
+				return err
+			}
+			defer func() { _ = cleanup() }()
+
+			init, err := c.CheckInitialized(context.Background())
+			if err != nil {
+				return err
+			}
+			Expect(init.Spec.UUID).To(Equal("test-uuid"))
+			return nil
+		}
+
+		BeforeEach(func() {
+			dir := GinkgoT().TempDir()
+			var caKey *ecdsa.PrivateKey
+			ca, caCert, caKey = writeCert(dir, "ca", "", true, nil, nil)
+			server, _, _ = writeCert(dir, "server", "dpservice.test", false, caCert, caKey)
+			client, _, _ = writeCert(dir, "client", "", false, caCert, caKey)
+		})
+
+		It("should verify the server against the given CA and server name", func() {
+			startServer(false)
+			Expect(checkInitialized(&DPDKClientOptions{CAFile: ca.certFile, ServerName: "dpservice.test"})).To(Succeed())
+		})
+
+		It("should fail if the server name does not match", func() {
+			startServer(false)
+			Expect(checkInitialized(&DPDKClientOptions{
+				CAFile:         ca.certFile,
+				ServerName:     "other.test",
+				ConnectTimeout: 500 * time.Millisecond,
+			})).NotTo(Succeed())
+		})
+
+		It("should skip verification if requested", func() {
+			startServer(false)
+			Expect(checkInitialized(&DPDKClientOptions{InsecureSkipVerify: true})).To(Succeed())
+		})
+
+		It("should fail to connect without TLS", func() {
+			startServer(false)
+			Expect(checkInitialized(&DPDKClientOptions{ConnectTimeout: 500 * time.Millisecond})).NotTo(Succeed())
+		})
+
+		It("should authenticate with a client certificate", func() {
+			startServer(true)
+			Expect(checkInitialized(&DPDKClientOptions{
+				CAFile:     ca.certFile,
+				ServerName: "dpservice.test",
+				CertFile:   client.certFile,
+				KeyFile:    client.keyFile,
+			})).To(Succeed())
+		})
+
+		It("should be rejected without a client certificate if the server requires one", func() {
+			startServer(true)
+			Expect(checkInitialized(&DPDKClientOptions{
+				CAFile:         ca.certFile,
+				ServerName:     "dpservice.test",
+				ConnectTimeout: 500 * time.Millisecond,
+			})).NotTo(Succeed())
+		})
+
+		It("should require cert and key file together", func() {
+			_, err := (&DPDKClientOptions{CertFile: client.certFile}).TransportCredentials()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("retries", func() {
+		var server *flakyServer
+
+		newClient := func(opts *DPDKClientOptions) client.Client {
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			srv := grpc.NewServer()
+			dpdkproto.RegisterDPDKironcoreServer(srv, server)
+			go func() {
+				defer GinkgoRecover()
+				_ = srv.Serve(lis)
+			}()
+			DeferCleanup(srv.Stop)
+
+			opts.Address = lis.Addr().String()
+			opts.ConnectTimeout = 2 * time.Second
+			c, cleanup, err := opts.NewClient(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(cleanup)
+			return c
+		}
+
+		BeforeEach(func() {
+			server = &flakyServer{failures: 2}
+		})
+
+		It("should retry reads", func() {
+			c := newClient(&DPDKClientOptions{Retries: 2, RetryBackoff: time.Millisecond})
+			_, err := c.ListInterfaces(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.calls.Load()).To(BeEquivalentTo(3))
+		})
+
+		It("should give up after the given number of retries", func() {
+			c := newClient(&DPDKClientOptions{Retries: 1, RetryBackoff: time.Millisecond})
+			_, err := c.ListInterfaces(context.Background())
+			Expect(status.Code(err)).To(Equal(codes.Unavailable))
+			Expect(server.calls.Load()).To(BeEquivalentTo(2))
+		})
+
+		It("should not retry creates that reached dpservice", func() {
+			c := newClient(&DPDKClientOptions{Retries: 2, RetryBackoff: time.Millisecond})
+			_, err := c.CreateInterface(context.Background(), &api.Interface{InterfaceMeta: api.InterfaceMeta{ID: "vm1"}})
+			Expect(status.Code(err)).To(Equal(codes.Unavailable))
+			Expect(server.calls.Load()).To(BeEquivalentTo(1))
+		})
+
+		It("should apply the request timeout to every attempt", func() {
+			server = &flakyServer{delay: time.Minute}
+			c := newClient(&DPDKClientOptions{RequestTimeout: 50 * time.Millisecond, Retries: 1, RetryBackoff: time.Millisecond})
+			_, err := c.ListInterfaces(context.Background())
+			Expect(status.Code(err)).To(Equal(codes.DeadlineExceeded))
+			Expect(server.calls.Load()).To(BeEquivalentTo(2))
+		})
+	})
+
+	Context("tracing", func() {
+		It("should log calls to stderr from verbosity 4 on", func() {
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			srv := grpc.NewServer()
+			dpdkproto.RegisterDPDKironcoreServer(srv, initializedServer{})
+			go func() {
+				defer GinkgoRecover()
+				_ = srv.Serve(lis)
+			}()
+			DeferCleanup(srv.Stop)
+
+			stderr, err := os.CreateTemp(GinkgoT().TempDir(), "stderr")
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(stderr.Close)
+			DeferCleanup(func(f *os.File) { os.Stderr = f }, os.Stderr)
+			os.Stderr = stderr
+
+			opts := &DPDKClientOptions{Address: lis.Addr().String(), ConnectTimeout: 2 * time.Second, Verbosity: 4}
+			c, cleanup, err := opts.NewClient(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(cleanup)
+
+			_, err = c.CheckInitialized(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			_, err = c.ListInterfaces(context.Background())
+			Expect(err).To(HaveOccurred())
+
+			data, err := os.ReadFile(stderr.Name())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(SatisfyAll(
+				MatchRegexp(`grpc /dpdkironcore\.v1\.DPDKironcore/CheckInitialized status=OK latency=\S+\n  request: \{\}\n  response: \{.*"uuid":\s*"test-uuid".*\}\n`),
+				MatchRegexp(`grpc /dpdkironcore\.v1\.DPDKironcore/ListInterfaces status=Unimplemented latency=\S+\n  request: \{\}\n  error: method ListInterfaces not implemented\n`),
+			))
+		})
+	})
+
+	Context("unix socket", func() {
+		It("should connect to unix:// addresses", func() {
+			socket := filepath.Join(GinkgoT().TempDir(), "dpservice.sock")
+			lis, err := net.Listen("unix", socket)
+			Expect(err).NotTo(HaveOccurred())
+
+			srv := grpc.NewServer()
+			dpdkproto.RegisterDPDKironcoreServer(srv, initializedServer{})
+			go func() {
+				defer GinkgoRecover()
+				_ = srv.Serve(lis)
+			}()
+			DeferCleanup(srv.Stop)
+
+			opts := &DPDKClientOptions{Address: "unix://" + socket, ConnectTimeout: 2 * time.Second}
+			c, cleanup, err := opts.NewClient(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(cleanup)
+
+			init, err := c.CheckInitialized(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(init.Spec.UUID).To(Equal("test-uuid"))
+		})
+	})
+})
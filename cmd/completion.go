@@ -4,12 +4,126 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/ironcore-dev/dpservice-go/client"
 	"github.com/spf13/cobra"
 )
 
+// CompletionTimeout limits connecting to dpservice and listing objects for a completion, so
+// completing never hangs if dpservice is unreachable.
+const CompletionTimeout = time.Second
+
+// liveCompletions completes the IDs of flags with the objects in dpservice.
+type liveCompletions struct {
+	// factory returns the client factory for the command being completed
+	factory func(cmd *cobra.Command) DPDKClientFactory
+	// loadBalancerIDs returns the known load balancers, as dpservice cannot list them. Optional.
+	loadBalancerIDs func() []string
+}
+
+// forCompletion returns a copy of the options connecting within CompletionTimeout, without
+// retries, tracing and recording.
+func (o *DPDKClientOptions) forCompletion() *DPDKClientOptions {
+	opts := *o
+	opts.ConnectTimeout = min(opts.ConnectTimeout, CompletionTimeout)
+	opts.Retries, opts.Verbosity, opts.Record = 0, 0, ""
+	opts.CheckVersion, opts.StrictVersion = false, false
+	opts.recorder, opts.replayer = nil, nil
+	return &opts
+}
+
+// register adds the completions to all commands below root having the flags.
+func (l *liveCompletions) register(root *cobra.Command) {
+	walkCommands(root, func(cmd *cobra.Command) {
+		if cmd.Flags().Lookup("interface-id") != nil {
+			_ = cmd.RegisterFlagCompletionFunc("interface-id", l.completeInterfaceIDs)
+		}
+		if cmd.Flags().Lookup("lb-id") != nil {
+			_ = cmd.RegisterFlagCompletionFunc("lb-id", l.completeLoadBalancerIDs)
+		}
+		// new rules need a new ID
+		if cmd.Flags().Lookup("rule-id") != nil && (cmd.Parent() == nil || cmd.Parent().Name() != "create") {
+			_ = cmd.RegisterFlagCompletionFunc("rule-id", l.completeRuleIDs)
+		}
+	})
+}
+
+func walkCommands(cmd *cobra.Command, f func(cmd *cobra.Command)) {
+	f(cmd)
+	for _, sub := range cmd.Commands() {
+		walkCommands(sub, f)
+	}
+}
+
+// list calls f with a client, giving up after CompletionTimeout. Errors are not reported, as there
+// is no way to show them while completing.
+func (l *liveCompletions) list(ctx context.Context, cmd *cobra.Command, f func(ctx context.Context, c client.Client) ([]string, error)) []string {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, CompletionTimeout)
+	defer cancel()
+
+	c, cleanup, err := l.factory(cmd).NewClient(ctx)
+	if err != nil {
+		return nil
+	}
+	defer DpdkClose(cleanup)
+
+	ids, err := f(ctx, c)
+	if err != nil {
+		return nil
+	}
+	return ids
+}
+
+func listInterfaceIDs(ctx context.Context, c client.Client) ([]string, error) {
+	list, err := c.ListInterfaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, iface := range list.Items {
+		ids = append(ids, iface.ID)
+	}
+	return ids, nil
+}
+
+func (l *liveCompletions) completeInterfaceIDs(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return l.list(cmd.Context(), cmd, listInterfaceIDs), cobra.ShellCompDirectiveNoFileComp
+}
+
+func (l *liveCompletions) completeLoadBalancerIDs(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if l.loadBalancerIDs == nil {
+		return cobra.AppendActiveHelp(nil, "dpservice cannot list load balancers"), cobra.ShellCompDirectiveNoFileComp
+	}
+	return l.loadBalancerIDs(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRuleIDs completes the firewall rules of the interface given with --interface-id.
+func (l *liveCompletions) completeRuleIDs(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	interfaceID, _ := cmd.Flags().GetString("interface-id")
+	if interfaceID == "" {
+		return cobra.AppendActiveHelp(nil, "set --interface-id to complete its rules"), cobra.ShellCompDirectiveNoFileComp
+	}
+	ids := l.list(cmd.Context(), cmd, func(ctx context.Context, c client.Client) ([]string, error) {
+		list, err := c.ListFirewallRules(ctx, interfaceID)
+		if err != nil {
+			return nil, err
+		}
+		var ids []string
+		for _, rule := range list.Items {
+			ids = append(ids, rule.Spec.RuleID)
+		}
+		return ids, nil
+	})
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
 // completionCmd represents the completion command
 var completionCmd = &cobra.Command{
 	Use:   "completion [bash|zsh|fish|powershell]",
@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+
+	. "github.com/ironcore-dev/dpservice-cli/cmd"
+	"github.com/ironcore-dev/dpservice-cli/fake"
+	"github.com/ironcore-dev/dpservice-go/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+)
+
+var _ = Describe("Completion", func() {
+	var address string
+
+	BeforeEach(func() {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		server := fake.NewServer()
+		go func() {
+			defer GinkgoRecover()
+			_ = server.Serve(lis)
+		}()
+		DeferCleanup(lis.Close)
+		address = lis.Addr().String()
+
+		ctx := context.Background()
+		c, cleanup, err := server.NewClient(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(server.Stop)
+		DeferCleanup(cleanup)
+
+		for _, id := range []string{"vm1", "vm2"} {
+			ipv4 := netip.MustParseAddr("10.0.0.1")
+			_, err := c.CreateInterface(ctx, &api.Interface{
+				InterfaceMeta: api.InterfaceMeta{ID: id},
+				Spec:          api.InterfaceSpec{VNI: 100, IPv4: &ipv4},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		src, dst := netip.MustParsePrefix("0.0.0.0/0"), netip.MustParsePrefix("10.0.0.1/32")
+		_, err = c.CreateFirewallRule(ctx, &api.FirewallRule{
+			FirewallRuleMeta: api.FirewallRuleMeta{InterfaceID: "vm2"},
+			Spec: api.FirewallRuleSpec{
+				RuleID:            "fr1",
+				TrafficDirection:  "Ingress",
+				FirewallAction:    "Accept",
+				SourcePrefix:      &src,
+				DestinationPrefix: &dst,
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	complete := func(args ...string) []string {
+		cmd := Command()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs(append([]string{cobra.ShellCompRequestCmd}, args...))
+		Expect(cmd.Execute()).To(Succeed())
+
+		var candidates []string
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			if !strings.HasPrefix(line, ":") {
+				candidates = append(candidates, line)
+			}
+		}
+		return candidates
+	}
+
+	It("should complete interface IDs", func() {
+		Expect(complete("--address", address, "list", "prefixes", "--interface-id", "")).To(Equal([]string{"vm1", "vm2"}))
+	})
+
+	It("should complete the firewall rules of the interface", func() {
+		Expect(complete("--address", address, "get", "firewallrule", "--interface-id=vm2", "--rule-id", "")).To(Equal([]string{"fr1"}))
+		Expect(complete("--address", address, "get", "firewallrule", "--interface-id=vm1", "--rule-id", "")).To(BeEmpty())
+	})
+
+	It("should give up quickly if dpservice is unreachable", func() {
+		start := time.Now()
+		Expect(complete("--address", "127.0.0.1:1", "--connect-timeout=1m", "list", "prefixes", "--interface-id", "")).To(BeEmpty())
+		Expect(time.Since(start)).To(BeNumerically("<", 2*CompletionTimeout))
+	})
+})
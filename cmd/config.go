@@ -0,0 +1,211 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ironcore-dev/dpservice-cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// EnvPrefix is the prefix of the environment variables setting persistent flags,
+// e.g. DPSERVICE_CLI_CONNECT_TIMEOUT for --connect-timeout.
+const EnvPrefix = "DPSERVICE_CLI_"
+
+// EnvName returns the environment variable for the flag name.
+func EnvName(name string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// Sources of flag values, from highest to lowest precedence.
+const (
+	SourceFlag    = "flag"
+	SourceEnv     = "env"
+	SourceConfig  = "config"
+	SourceDefault = "default"
+)
+
+// FlagValue is the effective value of a persistent flag and where it came from.
+type FlagValue struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+	// Origin details the source, e.g. the environment variable or the config file context.
+	Origin string `json:"origin,omitempty"`
+}
+
+type ConfigOptions struct {
+	ConfigFile string
+	Context    string
+
+	values []FlagValue
+}
+
+func (o *ConfigOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.ConfigFile, "config", o.ConfigFile, "Config file with named contexts. Defaults to ~/.config/dpservice-cli/config.yaml.")
+	fs.StringVar(&o.Context, "context", o.Context, "Context of the config file to use. Defaults to the current context of the config file.")
+}
+
+// Values returns the effective values of the persistent flags determined by Apply.
+func (o *ConfigOptions) Values() []FlagValue {
+	return o.values
+}
+
+// Apply sets the persistent flags of root that were not set on the command line, taking the value
+// from the environment, from the config file or keeping the default, in this order.
+// fs are the flags of the executed command, which may shadow persistent flags of root.
+func (o *ConfigOptions) Apply(root, fs *pflag.FlagSet) error {
+	o.values = nil
+
+	// the config file and context can only be selected by flags and the environment
+	for _, name := range []string{"config", "context"} {
+		if err := o.resolve(fs, name, nil); err != nil {
+			return err
+		}
+	}
+
+	configValues, err := o.loadConfigValues()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	root.VisitAll(func(f *pflag.Flag) {
+		if f.Name == "config" || f.Name == "context" {
+			return
+		}
+		if err := o.resolve(fs, f.Name, configValues); err != nil {
+			errs = append(errs, err)
+		}
+	})
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+type configValue struct {
+	value  string
+	origin string
+}
+
+func (o *ConfigOptions) resolve(fs *pflag.FlagSet, name string, configValues map[string]configValue) error {
+	f := fs.Lookup(name)
+	if f == nil {
+		return nil
+	}
+
+	value := FlagValue{Name: name, Source: SourceDefault}
+	env := EnvName(name)
+	if f.Changed {
+		value.Source = SourceFlag
+	} else if v, ok := os.LookupEnv(env); ok {
+		if err := fs.Set(name, v); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", env, err)
+		}
+		value.Source, value.Origin = SourceEnv, env
+	} else if v, ok := configValues[name]; ok {
+		if err := fs.Set(name, v.value); err != nil {
+			return fmt.Errorf("invalid value for %s in %s: %w", name, v.origin, err)
+		}
+		value.Source, value.Origin = SourceConfig, v.origin
+	}
+
+	value.Value = f.Value.String()
+	o.values = append(o.values, value)
+	return nil
+}
+
+// loadConfigValues returns the flag values of the config file: the values of the selected context,
+// falling back to the flags of the config file.
+func (o *ConfigOptions) loadConfigValues() (map[string]configValue, error) {
+	path, mustExist := o.ConfigFile, o.ConfigFile != ""
+	if path == "" {
+		var err error
+		if path, err = config.DefaultPath(); err != nil {
+			// without a config directory there is no default config file
+			return nil, nil
+		}
+	}
+
+	cfg, err := config.Load(path, mustExist)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err := cfg.Context(o.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]configValue)
+	for name, value := range cfg.Flags {
+		values[name] = configValue{value: value, origin: path}
+	}
+	if ctx != nil {
+		for _, f := range contextFlags(ctx) {
+			if f.value != "" {
+				values[f.name] = configValue{value: f.value, origin: fmt.Sprintf("%s (context %s)", path, ctx.Name)}
+			}
+		}
+	}
+	return values, nil
+}
+
+type contextFlag struct {
+	name  string
+	value string
+}
+
+// contextFlags maps the fields of ctx to the flags they set. Unset fields map to empty values.
+func contextFlags(ctx *config.Context) []contextFlag {
+	flags := []contextFlag{
+		{"address", ctx.Address},
+		{"connect-timeout", ctx.ConnectTimeout},
+		{"output", ctx.Output},
+	}
+	if tls := ctx.TLS; tls != nil {
+		flags = append(flags,
+			contextFlag{"tls", boolFlagValue(tls.Enabled)},
+			contextFlag{"ca-file", tls.CAFile},
+			contextFlag{"cert-file", tls.CertFile},
+			contextFlag{"key-file", tls.KeyFile},
+			contextFlag{"server-name", tls.ServerName},
+			contextFlag{"insecure-skip-verify", boolFlagValue(tls.InsecureSkipVerify)},
+		)
+	}
+	return flags
+}
+
+func boolFlagValue(b bool) string {
+	if !b {
+		return ""
+	}
+	return strconv.FormatBool(b)
+}
+
+func Config(configOptions *ConfigOptions, rendererOptions *RendererOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:  "config",
+		Args: cobra.NoArgs,
+		RunE: SubcommandRequired,
+	}
+
+	subcommands := []*cobra.Command{
+		ConfigView(configOptions, rendererOptions),
+	}
+
+	cmd.Short = fmt.Sprintf("Shows the configuration, one of %v", CommandNames(subcommands))
+	cmd.Long = fmt.Sprintf("Shows the configuration, one of %v", CommandNames(subcommands))
+
+	cmd.AddCommand(
+		subcommands...,
+	)
+
+	return cmd
+}
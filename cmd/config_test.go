@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+
+	. "github.com/ironcore-dev/dpservice-cli/cmd"
+	dpdkproto "github.com/ironcore-dev/dpservice-go/proto"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+)
+
+var _ = Describe("ConfigOptions", func() {
+	var (
+		dir        string
+		configFile string
+		socket     string
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		socket = filepath.Join(dir, "dpservice.sock")
+		configFile = filepath.Join(dir, "config.yaml")
+		Expect(os.WriteFile(configFile, []byte(`
+currentContext: unreachable
+contexts:
+- name: unreachable
+  address: localhost:1
+  connectTimeout: 100ms
+- name: local
+  address: unix://`+socket+`
+  output: json
+`), 0600)).To(Succeed())
+
+		lis, err := net.Listen("unix", socket)
+		Expect(err).NotTo(HaveOccurred())
+		srv := grpc.NewServer()
+		dpdkproto.RegisterDPDKironcoreServer(srv, initializedServer{})
+		go func() {
+			defer GinkgoRecover()
+			_ = srv.Serve(lis)
+		}()
+		DeferCleanup(srv.Stop)
+	})
+
+	run := func(args ...string) error {
+		cmd := Command()
+		cmd.SetArgs(append(args, "--config", configFile))
+		return cmd.Execute()
+	}
+
+	It("should use the context selected by --context", func() {
+		Expect(run("get", "init", "--context", "local")).To(Succeed())
+	})
+
+	It("should use the context selected by the environment", func() {
+		GinkgoT().Setenv(EnvName("context"), "local")
+		Expect(run("get", "init")).To(Succeed())
+	})
+
+	It("should use the current context by default", func() {
+		Expect(run("get", "init")).NotTo(Succeed())
+	})
+
+	It("should prefer flags over the context", func() {
+		Expect(run("get", "init", "--context", "unreachable", "--address", "unix://"+socket)).To(Succeed())
+	})
+
+	It("should fail for unknown contexts", func() {
+		Expect(run("get", "init", "--context", "missing")).To(MatchError(ContainSubstring("not found")))
+	})
+
+	It("should prefer the environment over the context", func() {
+		GinkgoT().Setenv(EnvName("address"), "unix://"+socket)
+		Expect(run("get", "init")).To(Succeed())
+	})
+
+	It("should fail for invalid environment values", func() {
+		GinkgoT().Setenv(EnvName("connect-timeout"), "soon")
+		Expect(run("get", "init")).To(MatchError(ContainSubstring(EnvName("connect-timeout"))))
+	})
+
+	It("should report the effective values and their sources", func() {
+		GinkgoT().Setenv(EnvName("connect-timeout"), "1s")
+		var out bytes.Buffer
+		cmd := Command()
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"config", "view", "--config", configFile, "--context", "local", "--output", "json"})
+		Expect(cmd.Execute()).To(Succeed())
+
+		var values []FlagValue
+		Expect(json.Unmarshal(out.Bytes(), &values)).To(Succeed())
+		Expect(values).To(ContainElements(
+			FlagValue{Name: "context", Value: "local", Source: SourceFlag},
+			FlagValue{Name: "output", Value: "json", Source: SourceFlag},
+			FlagValue{Name: "connect-timeout", Value: "1s", Source: SourceEnv, Origin: EnvName("connect-timeout")},
+			FlagValue{Name: "address", Value: "unix://" + socket, Source: SourceConfig, Origin: configFile + " (context local)"},
+			FlagValue{Name: "tls", Value: "false", Source: SourceDefault},
+		))
+	})
+})
@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func ConfigView(configOptions *ConfigOptions, rendererOptions *RendererOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "view",
+		Short:   "Show the effective values of the global flags and where they come from",
+		Long:    "Show the effective values of the global flags and where they come from: the command line (flag), an environment variable (env), the config file (config) or the default.",
+		Example: "DPSERVICE_CLI_ADDRESS=node1:1337 dpservice-cli config view",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunConfigView(cmd.OutOrStdout(), rendererOptions, configOptions.Values())
+		},
+	}
+	return cmd
+}
+
+func RunConfigView(w io.Writer, rendererOptions *RendererOptions, values []FlagValue) error {
+	switch rendererOptions.Output {
+	case "json", "yaml":
+		renderer, err := rendererOptions.NewRenderer("", w)
+		if err != nil {
+			return fmt.Errorf("error creating renderer: %w", err)
+		}
+		return renderer.Render(values)
+	default:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "NAME\tVALUE\tSOURCE\tORIGIN")
+		for _, value := range values {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", value.Name, value.Value, value.Source, value.Origin)
+		}
+		return tw.Flush()
+	}
+}
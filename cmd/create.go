@@ -6,6 +6,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
 	"strings"
@@ -15,11 +16,13 @@ import (
 	"github.com/ironcore-dev/dpservice-cli/sources"
 	"github.com/ironcore-dev/dpservice-go/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func Create(factory DPDKClientFactory) *cobra.Command {
 	rendererOptions := &RendererOptions{Output: "name"}
 	sourcesOptions := &SourcesOptions{}
+	createOptions := &CreateOptions{}
 
 	cmd := &cobra.Command{
 		Use:     "create [command]",
@@ -27,13 +30,14 @@ func Create(factory DPDKClientFactory) *cobra.Command {
 		Args:    cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
-			return RunCreate(ctx, factory, rendererOptions, sourcesOptions)
+			return RunCreate(ctx, factory, rendererOptions, sourcesOptions, cmd.ErrOrStderr(), *createOptions)
 		},
 	}
 
 	rendererOptions.AddFlags(cmd.PersistentFlags())
 
 	sourcesOptions.AddFlags(cmd.Flags())
+	createOptions.AddFlags(cmd.Flags())
 
 	subcommands := []*cobra.Command{
 		CreateInterface(factory, rendererOptions),
@@ -58,12 +62,33 @@ func Create(factory DPDKClientFactory) *cobra.Command {
 	return cmd
 }
 
+type CreateOptions struct {
+	PruneOptions
+	BulkOptions
+	Atomic bool
+}
+
+func (o *CreateOptions) AddFlags(fs *pflag.FlagSet) {
+	o.PruneOptions.AddFlags(fs)
+	o.BulkOptions.AddFlags(fs)
+	fs.BoolVar(&o.Atomic, "atomic", o.Atomic, "Stop at the first failure and delete all objects created so far.")
+}
+
 func RunCreate(
 	ctx context.Context,
 	dpdkClientFactory DPDKClientFactory,
 	rendererFactory RendererFactory,
 	sourcesReaderFactory SourcesReaderFactory,
+	errOut io.Writer,
+	opts CreateOptions,
 ) error {
+	if err := opts.PruneOptions.Validate(); err != nil {
+		return err
+	}
+	if err := opts.BulkOptions.Validate(); err != nil {
+		return err
+	}
+
 	client, cleanup, err := dpdkClientFactory.NewClient(ctx)
 	if err != nil {
 		return fmt.Errorf("error creating dpdk client: %w", err)
@@ -91,24 +116,91 @@ func RunCreate(
 		return fmt.Errorf("error collecting objects: %w", err)
 	}
 
-	for _, obj := range objs {
-		res, err := dc.Create(ctx, obj)
-		if strings.Contains(err.Error(), errors.StatusErrorString) {
-			r := reflect.ValueOf(res)
-			err := reflect.Indirect(r).FieldByName("Status").FieldByName("Error")
-			msg := reflect.Indirect(r).FieldByName("Status").FieldByName("Message")
-			fmt.Printf("Error creating %T: Server error: %v %v\n", res, err, msg)
+	levels, err := runtime.DefaultScheme.CreationLevels(objs)
+	if err != nil {
+		return fmt.Errorf("error sorting objects: %w", err)
+	}
+
+	summary := &BulkSummary{Operation: "create"}
+	if err := runBulk(ctx, levels, opts.Parallelism, opts.Atomic, dc.Create, func(results []bulkResult) error {
+		for _, result := range results {
+			summary.add(result)
+			if result.Skipped {
+				continue
+			}
+			if result.Err != nil {
+				if opts.SummaryOutput == "text" {
+					printCreateError(errOut, result)
+				}
+				continue
+			}
+
+			if err := renderer.Render(result.Res); err != nil {
+				return fmt.Errorf("error rendering %T: %w", result.Obj, err)
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if opts.Atomic && summary.Failed > 0 {
+		if err := rollback(ctx, dc, rendererFactory, errOut, summary); err != nil {
+			return err
+		}
+	}
+
+	if opts.Prune && summary.Failed == 0 {
+		if err := RunPrune(ctx, client, rendererFactory, errOut, objs, opts.PruneOptions); err != nil {
+			return err
+		}
+	}
+
+	if err := summary.Write(errOut, opts.SummaryOutput); err != nil {
+		return fmt.Errorf("error writing summary: %w", err)
+	}
+	return summary.Err()
+}
+
+func printCreateError(w io.Writer, result bulkResult) {
+	if r := reflect.Indirect(reflect.ValueOf(result.Res)); r.IsValid() && strings.Contains(result.Err.Error(), errors.StatusErrorString) {
+		err := r.FieldByName("Status").FieldByName("Error")
+		msg := r.FieldByName("Status").FieldByName("Message")
+		fmt.Fprintf(w, "Error creating %T: Server error: %v %v\n", result.Res, err, msg)
+		return
+	}
+	fmt.Fprintf(w, "Error creating %T %s: %v\n", result.Obj, objectKey(result.Obj), result.Err)
+}
+
+// rollback deletes the objects created so far in reverse order and marks them as rolled back in the summary.
+// Objects that cannot be deleted are reported to errOut and marked as rollback failed.
+func rollback(ctx context.Context, dc dynamic.Client, rendererFactory RendererFactory, errOut io.Writer, summary *BulkSummary) error {
+	renderer, err := rendererFactory.NewRenderer("rolled back", os.Stdout)
+	if err != nil {
+		return fmt.Errorf("error creating renderer: %w", err)
+	}
+
+	for i := len(summary.Objects) - 1; i >= 0; i-- {
+		result := &summary.Objects[i]
+		if result.Result != resultSucceeded {
 			continue
 		}
-		if err != nil {
-			fmt.Printf("Error creating %T: %v\n", obj, err)
+
+		if _, err := dc.Delete(ctx, result.obj); err != nil {
+			fmt.Fprintf(errOut, "Error rolling back %T %s: %v\n", result.obj, result.Key, err)
+			result.Result = resultRollbackFailed
+			result.Message = err.Error()
+			summary.Succeeded--
+			summary.RollbackFailed++
 			continue
 		}
 
-		if err := renderer.Render(res); err != nil {
-			return fmt.Errorf("error rendering %T: %w", obj, err)
+		result.Result = resultRolledBack
+		summary.Succeeded--
+		summary.RolledBack++
+		if err := renderer.Render(result.obj); err != nil {
+			return fmt.Errorf("error rendering %T: %w", result.obj, err)
 		}
 	}
-
 	return nil
 }
@@ -6,6 +6,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
 	"strings"
@@ -15,25 +16,35 @@ import (
 	"github.com/ironcore-dev/dpservice-cli/sources"
 	"github.com/ironcore-dev/dpservice-go/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func Delete(factory DPDKClientFactory) *cobra.Command {
 	sourcesOptions := &SourcesOptions{}
 	rendererOptions := &RendererOptions{Output: "name"}
+	deleteOptions := &DeleteOptions{}
 
 	cmd := &cobra.Command{
-		Use:     "delete [command]",
+		Use:     "delete [command | <kind>/<key>...]",
 		Aliases: []string{"del"},
-		Args:    cobra.NoArgs,
+		Example: "dpservice-cli delete fwrule/vm1/r12 interface/vm1",
+		Args:    cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
-			return RunDelete(ctx, factory, rendererOptions, sourcesOptions)
+			if len(args) > 0 {
+				if len(sourcesOptions.Filename) > 0 {
+					return fmt.Errorf("object references cannot be combined with --filename")
+				}
+				return RunDeleteObjects(ctx, factory, rendererOptions, cmd.ErrOrStderr(), args)
+			}
+			return RunDelete(ctx, factory, rendererOptions, sourcesOptions, cmd.ErrOrStderr(), *deleteOptions)
 		},
 	}
 
 	rendererOptions.AddFlags(cmd.PersistentFlags())
 
 	sourcesOptions.AddFlags(cmd.Flags())
+	deleteOptions.AddFlags(cmd.Flags())
 
 	subcommands := []*cobra.Command{
 		DeleteInterface(factory, rendererOptions),
@@ -56,12 +67,26 @@ func Delete(factory DPDKClientFactory) *cobra.Command {
 	return cmd
 }
 
+type DeleteOptions struct {
+	BulkOptions
+}
+
+func (o *DeleteOptions) AddFlags(fs *pflag.FlagSet) {
+	o.BulkOptions.AddFlags(fs)
+}
+
 func RunDelete(
 	ctx context.Context,
 	dpdkClientFactory DPDKClientFactory,
 	rendererFactory RendererFactory,
 	sourcesReaderFactory SourcesReaderFactory,
+	errOut io.Writer,
+	opts DeleteOptions,
 ) error {
+	if err := opts.BulkOptions.Validate(); err != nil {
+		return err
+	}
+
 	client, cleanup, err := dpdkClientFactory.NewClient(ctx)
 	if err != nil {
 		return fmt.Errorf("error creating dpdk client: %w", err)
@@ -89,19 +114,87 @@ func RunDelete(
 		return fmt.Errorf("error collecting objects: %w", err)
 	}
 
-	for _, obj := range objs {
-		key := dynamic.ObjectKeyFromObject(obj)
-
-		res, err := dc.Delete(ctx, obj)
-		if strings.Contains(err.Error(), errors.StatusErrorString) {
-			r := reflect.ValueOf(res)
-			err := reflect.Indirect(r).FieldByName("Status").FieldByName("Error")
-			msg := reflect.Indirect(r).FieldByName("Status").FieldByName("Message")
-			fmt.Printf("Error deleting %T %s: Server error: %v %v\n", res, key, err, msg)
-			continue
+	levels, err := runtime.DefaultScheme.DeletionLevels(objs)
+	if err != nil {
+		return fmt.Errorf("error sorting objects: %w", err)
+	}
+
+	summary := &BulkSummary{Operation: "delete"}
+	if err := runBulk(ctx, levels, opts.Parallelism, false, dc.Delete, func(results []bulkResult) error {
+		for _, result := range results {
+			summary.add(result)
+			if result.Skipped {
+				continue
+			}
+			if result.Err != nil {
+				if opts.SummaryOutput == "text" {
+					printDeleteError(errOut, result)
+				}
+				continue
+			}
+
+			if err := renderer.Render(result.Obj); err != nil {
+				return fmt.Errorf("error rendering %T: %w", result.Obj, err)
+			}
 		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := summary.Write(errOut, opts.SummaryOutput); err != nil {
+		return fmt.Errorf("error writing summary: %w", err)
+	}
+	return summary.Err()
+}
+
+// RunDeleteObjects deletes the objects referenced as <kind>/<key>, e.g. interface/vm1.
+// The objects are looked up first, so they can be deleted in dependency order.
+func RunDeleteObjects(
+	ctx context.Context,
+	dpdkClientFactory DPDKClientFactory,
+	rendererFactory RendererFactory,
+	errOut io.Writer,
+	refs []string,
+) error {
+	keys, err := ParseObjectRefs(refs)
+	if err != nil {
+		return err
+	}
+
+	client, cleanup, err := dpdkClientFactory.NewClient(ctx)
+	if err != nil {
+		return fmt.Errorf("error creating dpdk client: %w", err)
+	}
+	defer DpdkClose(cleanup)
+
+	dc := dynamic.NewFromStructured(client)
+
+	renderer, err := rendererFactory.NewRenderer("deleted", os.Stdout)
+	if err != nil {
+		return fmt.Errorf("error creating renderer: %w", err)
+	}
+
+	failed := 0
+	objs := make([]any, 0, len(keys))
+	for _, key := range keys {
+		obj, err := dc.Get(ctx, key)
 		if err != nil {
-			fmt.Printf("Error deleting %T %s: %v\n", res, key, err)
+			fmt.Fprintf(errOut, "Error deleting %s %s: %v\n", kindForKey(key), key, err)
+			failed++
+			continue
+		}
+		objs = append(objs, obj)
+	}
+
+	if err := runtime.DefaultScheme.SortForDeletion(objs); err != nil {
+		return fmt.Errorf("error sorting objects: %w", err)
+	}
+
+	for _, obj := range objs {
+		if res, err := dc.Delete(ctx, obj); err != nil {
+			printDeleteError(errOut, bulkResult{Obj: obj, Res: res, Err: err})
+			failed++
 			continue
 		}
 
@@ -110,5 +203,19 @@ func RunDelete(
 		}
 	}
 
+	if failed > 0 {
+		return fmt.Errorf("%d of %d objects failed", failed, len(keys))
+	}
 	return nil
 }
+
+func printDeleteError(w io.Writer, result bulkResult) {
+	key := objectKey(result.Obj)
+	if r := reflect.Indirect(reflect.ValueOf(result.Res)); r.IsValid() && strings.Contains(result.Err.Error(), errors.StatusErrorString) {
+		err := r.FieldByName("Status").FieldByName("Error")
+		msg := r.FieldByName("Status").FieldByName("Message")
+		fmt.Fprintf(w, "Error deleting %T %s: Server error: %v %v\n", result.Res, key, err, msg)
+		return
+	}
+	fmt.Fprintf(w, "Error deleting %T %s: %v\n", result.Obj, key, result.Err)
+}
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ironcore-dev/dpservice-cli/diff"
+	"github.com/ironcore-dev/dpservice-cli/dpdk/client/dynamic"
+	"github.com/ironcore-dev/dpservice-cli/dpdk/runtime"
+	"github.com/ironcore-dev/dpservice-cli/sources"
+	"github.com/spf13/cobra"
+)
+
+func Diff(factory DPDKClientFactory) *cobra.Command {
+	sourcesOptions := &SourcesOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "diff <-f>",
+		Short:   "Shows differences between objects in files and the running dpservice",
+		Long:    "Shows differences between objects in files and the running dpservice. Exits with a non-zero code if any object differs or does not exist.",
+		Example: "dpservice-cli diff -f /tmp/interfaces.yaml",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunDiff(cmd.Context(), factory, sourcesOptions)
+		},
+	}
+
+	sourcesOptions.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func RunDiff(
+	ctx context.Context,
+	dpdkClientFactory DPDKClientFactory,
+	sourcesReaderFactory SourcesReaderFactory,
+) error {
+	client, cleanup, err := dpdkClientFactory.NewClient(ctx)
+	if err != nil {
+		return fmt.Errorf("error creating dpdk client: %w", err)
+	}
+	defer DpdkClose(cleanup)
+
+	dc := dynamic.NewFromStructured(client)
+
+	iterator, err := sourcesReaderFactory.NewIterator()
+	if err != nil {
+		return fmt.Errorf("error creating sources iterator: %w", err)
+	}
+
+	objs, rawSpecs, err := sources.CollectObjectsWithRawSpecs(iterator, runtime.DefaultScheme)
+	if err != nil {
+		return fmt.Errorf("error collecting objects: %w", err)
+	}
+
+	drifted := 0
+	for _, obj := range objs {
+		kind, err := runtime.DefaultScheme.KindFor(obj)
+		if err != nil {
+			return err
+		}
+		key, err := dynamic.ObjectKeyFromObject(obj)
+		if err != nil {
+			return fmt.Errorf("error getting key of %s: %w", kind, err)
+		}
+
+		live, err := dc.Get(ctx, key)
+		if err != nil {
+			if !dynamic.IsNotFound(err) {
+				return fmt.Errorf("error getting %s %s: %w", kind, key, err)
+			}
+			live = nil
+		}
+
+		changes, err := diff.SourceSpec(obj, live, rawSpecs[obj])
+		if err != nil {
+			return fmt.Errorf("error comparing %s %s: %w", kind, key, err)
+		}
+		if len(changes) == 0 {
+			continue
+		}
+
+		drifted++
+		if err := diff.Unified(os.Stdout, fmt.Sprintf("%s/%s", kind, key), changes); err != nil {
+			return fmt.Errorf("error writing diff of %s %s: %w", kind, key, err)
+		}
+	}
+
+	if drifted > 0 {
+		return fmt.Errorf("%d of %d objects differ from dpservice", drifted, len(objs))
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ironcore-dev/dpservice-go/api"
+	"github.com/ironcore-dev/dpservice-go/client"
+	dpdkproto "github.com/ironcore-dev/dpservice-go/proto"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+const (
+	CheckOK      = "OK"
+	CheckWarning = "WARNING"
+	CheckFailed  = "FAILED"
+	CheckSkipped = "SKIPPED"
+)
+
+// DoctorCheck is the result of a single check of the doctor command.
+type DoctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Hint suggests how to fix a failed or suspicious check.
+	Hint string `json:"hint,omitempty"`
+}
+
+// dialer is implemented by client factories able to report the state of the gRPC connection.
+type dialer interface {
+	Dial(ctx context.Context) (*grpc.ClientConn, error)
+}
+
+var _ dialer = (*DPDKClientOptions)(nil)
+
+func Doctor(dpdkClientFactory DPDKClientFactory, rendererOptions *RendererOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the connection to dpservice and its health",
+		Long: `Check the connection to dpservice and its health: the gRPC connection state, the latency of GetVersion,
+the client and service protocol versions, the initialization, the number of interfaces and load balancers
+and the capture state. Every failed check prints a hint and makes the command exit with a non-zero code.`,
+		Example: "dpservice-cli doctor --address=node1:1337",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunDoctor(
+				cmd.Context(),
+				dpdkClientFactory,
+				cmd.OutOrStdout(),
+				rendererOptions,
+			)
+		},
+	}
+	return cmd
+}
+
+func RunDoctor(
+	ctx context.Context,
+	dpdkClientFactory DPDKClientFactory,
+	w io.Writer,
+	rendererOptions *RendererOptions,
+) error {
+	checks := runDoctorChecks(ctx, dpdkClientFactory)
+
+	if err := writeDoctorChecks(w, rendererOptions, checks); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, check := range checks {
+		if check.Status == CheckFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	return nil
+}
+
+func runDoctorChecks(ctx context.Context, dpdkClientFactory DPDKClientFactory) []DoctorCheck {
+	c, cleanup, connection := doctorConnect(ctx, dpdkClientFactory)
+	checks := []DoctorCheck{connection}
+	if c == nil {
+		for _, name := range []string{"version", "protocol", "initialized", "interfaces", "loadbalancers", "capture"} {
+			checks = append(checks, DoctorCheck{Name: name, Status: CheckSkipped, Detail: "not connected"})
+		}
+		return checks
+	}
+	defer DpdkClose(cleanup)
+
+	version, versionCheck := doctorVersion(ctx, c)
+	checks = append(checks, versionCheck, doctorProtocol(version))
+	checks = append(checks,
+		doctorInitialized(ctx, c),
+		doctorInterfaces(ctx, c),
+		DoctorCheck{
+			Name:   "loadbalancers",
+			Status: CheckSkipped,
+			Detail: "dpservice cannot list load balancers",
+		},
+		doctorCapture(ctx, c),
+	)
+	return checks
+}
+
+func doctorConnect(ctx context.Context, dpdkClientFactory DPDKClientFactory) (client.Client, func() error, DoctorCheck) {
+	check := DoctorCheck{Name: "connection"}
+	hint := "Check that dpservice is running and listening on --address and that the TLS flags match its configuration. Raise --connect-timeout for slow networks."
+
+	start := time.Now()
+	if d, ok := dpdkClientFactory.(dialer); ok {
+		conn, err := d.Dial(ctx)
+		if err != nil {
+			check.Status, check.Detail, check.Hint = CheckFailed, err.Error(), hint
+			return nil, nil, check
+		}
+		check.Status = CheckOK
+		check.Detail = fmt.Sprintf("%s to %s in %s", conn.GetState(), conn.Target(), time.Since(start).Round(time.Microsecond))
+		return client.NewClient(dpdkproto.NewDPDKironcoreClient(conn)), conn.Close, check
+	}
+
+	c, cleanup, err := dpdkClientFactory.NewClient(ctx)
+	if err != nil {
+		check.Status, check.Detail, check.Hint = CheckFailed, err.Error(), hint
+		return nil, nil, check
+	}
+	check.Status = CheckOK
+	check.Detail = fmt.Sprintf("connected in %s", time.Since(start).Round(time.Microsecond))
+	return c, cleanup, check
+}
+
+func doctorVersion(ctx context.Context, c client.Client) (*api.Version, DoctorCheck) {
+	check := DoctorCheck{Name: "version"}
+
+	start := time.Now()
+	version, err := getServiceVersion(ctx, c)
+	latency := time.Since(start).Round(time.Microsecond)
+	if err != nil {
+		check.Status, check.Detail = CheckFailed, err.Error()
+		check.Hint = "The connection is up but dpservice does not answer. Check the dpservice logs and whether it is overloaded."
+		return nil, check
+	}
+	check.Status = CheckOK
+	check.Detail = fmt.Sprintf("dpservice %s, round trip %s", version.Spec.ServiceVersion, latency)
+	return version, check
+}
+
+func doctorProtocol(version *api.Version) DoctorCheck {
+	check := DoctorCheck{Name: "protocol"}
+	if version == nil {
+		check.Status, check.Detail = CheckSkipped, "version unknown"
+		return check
+	}
+
+	serviceProtocol := version.Spec.ServiceProtocol
+	check.Detail = fmt.Sprintf("client %s, service %s", ClientProtocol, serviceProtocol)
+	if !CompatibleProtocols(ClientProtocol, serviceProtocol) {
+		check.Status = CheckWarning
+		check.Hint = "dpservice-cli was built against an incompatible protocol version. Use a dpservice-cli matching the dpservice version."
+		return check
+	}
+	check.Status = CheckOK
+	return check
+}
+
+func doctorInitialized(ctx context.Context, c client.Client) DoctorCheck {
+	check := DoctorCheck{Name: "initialized"}
+	init, err := c.CheckInitialized(ctx)
+	if err != nil {
+		check.Status, check.Detail = CheckFailed, err.Error()
+		check.Hint = "dpservice is not initialized. Run 'dpservice-cli init' or check that the orchestration initializing it is running."
+		return check
+	}
+	check.Status = CheckOK
+	check.Detail = fmt.Sprintf("uuid %s", init.Spec.UUID)
+	return check
+}
+
+func doctorInterfaces(ctx context.Context, c client.Client) DoctorCheck {
+	check := DoctorCheck{Name: "interfaces"}
+	list, err := c.ListInterfaces(ctx)
+	if err != nil {
+		check.Status, check.Detail = CheckFailed, err.Error()
+		check.Hint = "Listing interfaces failed. Check the dpservice logs."
+		return check
+	}
+	check.Status = CheckOK
+	check.Detail = fmt.Sprintf("%d interfaces", len(list.Items))
+	return check
+}
+
+func doctorCapture(ctx context.Context, c client.Client) DoctorCheck {
+	check := DoctorCheck{Name: "capture"}
+	capture, err := c.CaptureStatus(ctx)
+	if err != nil {
+		check.Status, check.Detail = CheckFailed, err.Error()
+		check.Hint = "Getting the capture status failed. Check the dpservice logs."
+		return check
+	}
+	check.Status = CheckOK
+	check.Detail = "inactive"
+	if capture.Spec.OperationStatus {
+		check.Detail = "active"
+		check.Hint = "Packet capturing slows down dpservice. Stop it with 'dpservice-cli capture stop' when done."
+	}
+	return check
+}
+
+func writeDoctorChecks(w io.Writer, rendererOptions *RendererOptions, checks []DoctorCheck) error {
+	switch rendererOptions.Output {
+	case "json", "yaml":
+		renderer, err := rendererOptions.NewRenderer("", w)
+		if err != nil {
+			return fmt.Errorf("error creating renderer: %w", err)
+		}
+		return renderer.Render(checks)
+	default:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "CHECK\tSTATUS\tDETAIL")
+		for _, check := range checks {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", check.Name, check.Status, check.Detail)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+
+		for _, check := range checks {
+			if check.Hint != "" {
+				fmt.Fprintf(w, "\nHint (%s): %s\n", check.Name, check.Hint)
+			}
+		}
+		return nil
+	}
+}
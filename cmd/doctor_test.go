@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"time"
+
+	. "github.com/ironcore-dev/dpservice-cli/cmd"
+	dpdkproto "github.com/ironcore-dev/dpservice-go/proto"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+)
+
+// healthyServer is a stand-in for an initialized dpservice without any interfaces.
+type healthyServer struct {
+	initializedServer
+	protocol string
+}
+
+func (s healthyServer) GetVersion(context.Context, *dpdkproto.GetVersionRequest) (*dpdkproto.GetVersionResponse, error) {
+	return &dpdkproto.GetVersionResponse{Status: &dpdkproto.Status{}, ServiceProtocol: s.protocol, ServiceVersion: "v1.0.0"}, nil
+}
+
+func (healthyServer) ListInterfaces(context.Context, *dpdkproto.ListInterfacesRequest) (*dpdkproto.ListInterfacesResponse, error) {
+	return &dpdkproto.ListInterfacesResponse{Status: &dpdkproto.Status{}}, nil
+}
+
+func (healthyServer) CaptureStatus(context.Context, *dpdkproto.CaptureStatusRequest) (*dpdkproto.CaptureStatusResponse, error) {
+	return &dpdkproto.CaptureStatusResponse{Status: &dpdkproto.Status{}}, nil
+}
+
+var _ = Describe("Doctor", func() {
+	var opts *DPDKClientOptions
+
+	startServer := func(srv dpdkproto.DPDKironcoreServer) {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		s := grpc.NewServer()
+		dpdkproto.RegisterDPDKironcoreServer(s, srv)
+		go func() {
+			defer GinkgoRecover()
+			_ = s.Serve(lis)
+		}()
+		DeferCleanup(s.Stop)
+		opts.Address = lis.Addr().String()
+	}
+
+	run := func() ([]DoctorCheck, error) {
+		var out bytes.Buffer
+		err := RunDoctor(context.Background(), opts, &out, &RendererOptions{Output: "json"})
+		var checks []DoctorCheck
+		Expect(json.Unmarshal(out.Bytes(), &checks)).To(Succeed())
+		return checks, err
+	}
+
+	BeforeEach(func() {
+		opts = &DPDKClientOptions{ConnectTimeout: 2 * time.Second}
+	})
+
+	It("should pass all checks of a healthy dpservice", func() {
+		startServer(healthyServer{protocol: strings.TrimSpace(dpdkproto.GeneratedFrom)})
+
+		checks, err := run()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checks).To(HaveLen(7))
+		Expect(checks).To(ContainElements(
+			HaveField("Name", "connection"),
+			DoctorCheck{Name: "initialized", Status: CheckOK, Detail: "uuid test-uuid"},
+			DoctorCheck{Name: "interfaces", Status: CheckOK, Detail: "0 interfaces"},
+			DoctorCheck{Name: "loadbalancers", Status: CheckSkipped, Detail: "dpservice cannot list load balancers"},
+			DoctorCheck{Name: "capture", Status: CheckOK, Detail: "inactive"},
+		))
+		Expect(checks[0].Status).To(Equal(CheckOK))
+		Expect(checks[0].Detail).To(HavePrefix("READY to " + opts.Address))
+	})
+
+	It("should warn about differing protocol versions", func() {
+		startServer(healthyServer{protocol: "v0.0.1"})
+
+		checks, err := run()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checks).To(ContainElement(SatisfyAll(
+			HaveField("Name", "protocol"),
+			HaveField("Status", CheckWarning),
+			HaveField("Detail", ContainSubstring("service v0.0.1")),
+		)))
+	})
+
+	It("should fail with hints for failed checks", func() {
+		startServer(initializedServer{})
+
+		checks, err := run()
+		Expect(err).To(MatchError("3 of 7 checks failed"))
+		for _, check := range checks {
+			if check.Status == CheckFailed {
+				Expect(check.Hint).NotTo(BeEmpty(), check.Name)
+			}
+		}
+	})
+
+	It("should skip all checks if it cannot connect", func() {
+		opts.Address = "127.0.0.1:1"
+		opts.ConnectTimeout = 100 * time.Millisecond
+
+		checks, err := run()
+		Expect(err).To(MatchError("1 of 7 checks failed"))
+		Expect(checks[0]).To(HaveField("Hint", ContainSubstring("--address")))
+		for _, check := range checks[1:] {
+			Expect(check.Status).To(Equal(CheckSkipped))
+		}
+	})
+})
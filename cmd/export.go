@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ghodss/yaml"
+	"github.com/ironcore-dev/dpservice-cli/dpdk/client/dynamic"
+	"github.com/ironcore-dev/dpservice-cli/dpdk/runtime"
+	"github.com/ironcore-dev/dpservice-go/api"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func Export(dpdkClientFactory DPDKClientFactory) *cobra.Command {
+	var (
+		opts ExportOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:     "export [--loadbalancer-ids] [-o]",
+		Short:   "Export the state of dpservice as objects that can be created again with create -f",
+		Example: "dpservice-cli export --loadbalancer-ids=lb1,lb2 -o yaml > backup.yaml",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunExport(
+				cmd.Context(),
+				dpdkClientFactory,
+				os.Stdout,
+				opts,
+			)
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+type ExportOptions struct {
+	Output          string
+	LoadBalancerIDs []string
+}
+
+func (o *ExportOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&o.Output, "output", "o", "yaml", "Output format. [json|yaml]")
+	fs.StringSliceVar(&o.LoadBalancerIDs, "loadbalancer-ids", o.LoadBalancerIDs, "IDs of load balancers to export, as dpservice cannot list them.")
+}
+
+// exportKinds is the order in which kinds are exported, parents before their children,
+// so the result can be created again in file order.
+var exportKinds = []string{
+	api.InterfaceKind,
+	api.PrefixKind,
+	api.LoadBalancerPrefixKind,
+	api.VirtualIPKind,
+	api.NatKind,
+	api.FirewallRuleKind,
+	api.LoadBalancerKind,
+	api.LoadBalancerTargetKind,
+	api.RouteKind,
+}
+
+func RunExport(
+	ctx context.Context,
+	dpdkClientFactory DPDKClientFactory,
+	w io.Writer,
+	opts ExportOptions,
+) error {
+	var encode func(obj map[string]any) error
+	switch opts.Output {
+	case "json":
+		enc := json.NewEncoder(w)
+		encode = func(obj map[string]any) error {
+			return enc.Encode(obj)
+		}
+	case "yaml":
+		encode = func(obj map[string]any) error {
+			jsonData, err := json.Marshal(obj)
+			if err != nil {
+				return err
+			}
+			data, err := yaml.JSONToYAML(jsonData)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(w, "---\n%s", data)
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported output format %q", opts.Output)
+	}
+
+	client, cleanup, err := dpdkClientFactory.NewClient(ctx)
+	if err != nil {
+		return fmt.Errorf("error creating dpdk client: %w", err)
+	}
+	defer DpdkClose(cleanup)
+
+	hints := make([]any, len(opts.LoadBalancerIDs))
+	for i, id := range opts.LoadBalancerIDs {
+		hints[i] = &api.LoadBalancer{LoadBalancerMeta: api.LoadBalancerMeta{ID: id}}
+	}
+	lister := &liveLister{dc: dynamic.NewFromStructured(client), hints: hints}
+
+	for _, kind := range exportKinds {
+		objs, err := lister.List(ctx, kind)
+		if err != nil {
+			return fmt.Errorf("error listing %s: %w", kind, err)
+		}
+
+		for _, obj := range objs {
+			exported, err := exportObject(obj)
+			if err != nil {
+				return fmt.Errorf("error exporting %s %s: %w", kind, objectKey(obj), err)
+			}
+			if err := encode(exported); err != nil {
+				return fmt.Errorf("error writing %s %s: %w", kind, objectKey(obj), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// exportStatusFields are the spec fields populated by dpservice. They are dropped on export,
+// as they cannot be set on creation and would be reported as drift when applying the export.
+var exportStatusFields = []string{"underlay_route", "virtual_function"}
+
+// exportObject converts obj into the kind/metadata/spec form read by create -f, dropping its status.
+func exportObject(obj any) (map[string]any, error) {
+	kind, err := runtime.DefaultScheme.KindFor(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	if spec, ok := m["spec"].(map[string]any); ok {
+		for _, field := range exportStatusFields {
+			delete(spec, field)
+		}
+	}
+
+	return map[string]any{
+		"kind":     kind,
+		"metadata": m["metadata"],
+		"spec":     m["spec"],
+	}, nil
+}
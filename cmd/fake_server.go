@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/ironcore-dev/dpservice-cli/fake"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func FakeServer() *cobra.Command {
+	var (
+		opts FakeServerOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "fake-server",
+		Short: "Run an in-memory dpservice for demos and tests",
+		Long: `Run an in-memory dpservice for demos and tests. It keeps the objects created through the gRPC API
+and answers with the error codes of dpservice, but does not handle any traffic. All state is lost on exit.`,
+		Example: "dpservice-cli fake-server --listen=localhost:1337\ndpservice-cli fake-server --listen=unix:///tmp/dpservice.sock",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunFakeServer(
+				cmd.Context(),
+				cmd.ErrOrStderr(),
+				opts,
+			)
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+type FakeServerOptions struct {
+	Listen string
+}
+
+func (o *FakeServerOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Listen, "listen", "localhost:1337", "Address to listen on, either host:port or unix://<path>.")
+}
+
+// listenNetwork splits a --listen address into the network and address for net.Listen.
+func listenNetwork(listen string) (string, string) {
+	if path, ok := strings.CutPrefix(listen, "unix://"); ok {
+		return "unix", path
+	}
+	return "tcp", listen
+}
+
+func RunFakeServer(ctx context.Context, w io.Writer, opts FakeServerOptions) error {
+	network, address := listenNetwork(opts.Listen)
+	lis, err := net.Listen(network, address)
+	if err != nil {
+		return fmt.Errorf("error listening on %s: %w", opts.Listen, err)
+	}
+	defer lis.Close()
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := fake.NewServer()
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Serve(lis) }()
+	fmt.Fprintf(w, "Serving fake dpservice on %s\n", lis.Addr())
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-errCh:
+		return fmt.Errorf("error serving fake dpservice: %w", err)
+	}
+}
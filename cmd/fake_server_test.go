@@ -0,0 +1,342 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/ironcore-dev/dpservice-cli/cmd"
+	"github.com/ironcore-dev/dpservice-cli/fake"
+	"github.com/ironcore-dev/dpservice-go/api"
+	"github.com/ironcore-dev/dpservice-go/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const fakeObjects = `kind: Interface
+metadata:
+  id: vm1
+spec:
+  vni: 100
+  primary_ipv4: 10.0.0.1
+---
+kind: Prefix
+metadata:
+  interface_id: vm1
+spec:
+  prefix: 10.0.1.0/24
+---
+kind: VirtualIP
+metadata:
+  interface_id: vm1
+spec:
+  vip_ip: 20.0.0.1
+`
+
+// undeletableInterfacesFactory hands out clients of a fake server that fail to delete interfaces.
+type undeletableInterfacesFactory struct {
+	*fake.Server
+}
+
+func (f undeletableInterfacesFactory) NewClient(ctx context.Context) (client.Client, func() error, error) {
+	c, cleanup, err := f.Server.NewClient(ctx)
+	return undeletableInterfacesClient{c}, cleanup, err
+}
+
+type undeletableInterfacesClient struct {
+	client.Client
+}
+
+func (c undeletableInterfacesClient) DeleteInterface(_ context.Context, id string, _ ...[]uint32) (*api.Interface, error) {
+	return nil, fmt.Errorf("cannot delete interface %s", id)
+}
+
+var _ = Describe("FakeServer", func() {
+	var (
+		ctx             = context.Background()
+		server          *fake.Server
+		c               client.Client
+		rendererOptions *RendererOptions
+		sourcesOptions  *SourcesOptions
+	)
+
+	BeforeEach(func() {
+		server = fake.NewServer()
+		DeferCleanup(server.Stop)
+
+		var (
+			cleanup func() error
+			err     error
+		)
+		c, cleanup, err = server.NewClient(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(cleanup)
+
+		filename := filepath.Join(GinkgoT().TempDir(), "objects.yaml")
+		Expect(os.WriteFile(filename, []byte(fakeObjects), 0o644)).To(Succeed())
+		rendererOptions = &RendererOptions{Output: "name"}
+		sourcesOptions = &SourcesOptions{Filename: []string{filename}}
+	})
+
+	bulkOptions := BulkOptions{Parallelism: 1, SummaryOutput: "text"}
+
+	It("should create, list and delete objects from files", func() {
+		Expect(RunCreate(ctx, server, rendererOptions, sourcesOptions, io.Discard, CreateOptions{BulkOptions: bulkOptions})).To(Succeed())
+
+		ifaces, err := c.ListInterfaces(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ifaces.Items).To(HaveLen(1))
+		Expect(ifaces.Items[0].Spec.IPv4.String()).To(Equal("10.0.0.1"))
+		prefixes, err := c.ListPrefixes(ctx, "vm1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(prefixes.Items).To(HaveLen(1))
+		vip, err := c.GetVirtualIP(ctx, "vm1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vip.Spec.IP.String()).To(Equal("20.0.0.1"))
+
+		Expect(RunListInterfaces(ctx, server, rendererOptions, ListInterfacesOptions{})).To(Succeed())
+
+		By("creating the objects again")
+		Expect(RunCreate(ctx, server, rendererOptions, sourcesOptions, io.Discard, CreateOptions{BulkOptions: bulkOptions})).
+			To(MatchError(Equal("3 of 3 objects failed")))
+
+		Expect(RunDelete(ctx, server, rendererOptions, sourcesOptions, io.Discard, DeleteOptions{BulkOptions: bulkOptions})).To(Succeed())
+		ifaces, err = c.ListInterfaces(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ifaces.Items).To(BeEmpty())
+	})
+
+	It("should stop at the first failure and roll back in atomic mode", func() {
+		filename := filepath.Join(GinkgoT().TempDir(), "failing.yaml")
+		Expect(os.WriteFile(filename, []byte(`kind: Interface
+metadata:
+  id: vm1
+spec:
+  vni: 100
+  primary_ipv4: 10.0.0.1
+---
+kind: Prefix
+metadata:
+  interface_id: vm2
+spec:
+  prefix: 10.0.2.0/24
+---
+kind: Prefix
+metadata:
+  interface_id: vm1
+spec:
+  prefix: 10.0.1.0/24
+`), 0o644)).To(Succeed())
+
+		opts := CreateOptions{BulkOptions: bulkOptions, Atomic: true}
+		Expect(RunCreate(ctx, server, rendererOptions, &SourcesOptions{Filename: []string{filename}}, io.Discard, opts)).
+			To(MatchError(Equal("1 of 3 objects failed, rolled back 1 objects")))
+
+		ifaces, err := c.ListInterfaces(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ifaces.Items).To(BeEmpty())
+	})
+
+	It("should report objects the rollback failed to delete", func() {
+		filename := filepath.Join(GinkgoT().TempDir(), "failing.yaml")
+		Expect(os.WriteFile(filename, []byte(fakeObjects+`---
+kind: Prefix
+metadata:
+  interface_id: vm2
+spec:
+  prefix: 10.0.2.0/24
+`), 0o644)).To(Succeed())
+
+		var errOut bytes.Buffer
+		opts := CreateOptions{BulkOptions: bulkOptions, Atomic: true}
+		Expect(RunCreate(ctx, undeletableInterfacesFactory{server}, rendererOptions, &SourcesOptions{Filename: []string{filename}}, &errOut, opts)).
+			To(MatchError(Equal("1 of 4 objects failed, rolled back 2 objects, rollback failed for 1 objects")))
+		Expect(errOut.String()).To(ContainSubstring("Error rolling back *api.Interface vm1: cannot delete interface vm1"))
+		Expect(errOut.String()).To(ContainSubstring("create: 0 succeeded, 1 failed, 0 skipped, 2 rolled back, 1 rollback failed"))
+	})
+
+	It("should write the JSON summary to the error output only", func() {
+		var out, errOut bytes.Buffer
+		renderer := bufferRendererFactory{RendererOptions: rendererOptions, buf: &out}
+		opts := CreateOptions{BulkOptions: BulkOptions{Parallelism: 1, SummaryOutput: "json"}}
+		Expect(RunCreate(ctx, server, renderer, sourcesOptions, &errOut, opts)).To(Succeed())
+		Expect(out.String()).To(Equal("interface/vm1 created\nprefix/10.0.1.0/24 created\nvirtualip/on interface: vm1 created\n"))
+
+		var summary BulkSummary
+		Expect(json.Unmarshal(errOut.Bytes(), &summary)).To(Succeed())
+		Expect(summary.Succeeded).To(Equal(3))
+		Expect(summary.Objects).To(HaveLen(3))
+	})
+
+	It("should skip the objects not started yet when the context is done", func() {
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		Expect(RunCreate(canceledCtx, server, rendererOptions, sourcesOptions, io.Discard, CreateOptions{BulkOptions: bulkOptions})).
+			To(MatchError(context.Canceled))
+
+		ifaces, err := c.ListInterfaces(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ifaces.Items).To(BeEmpty())
+	})
+
+	It("should not report skipped objects as deleted when the context is done", func() {
+		Expect(RunCreate(ctx, server, rendererOptions, sourcesOptions, io.Discard, CreateOptions{BulkOptions: bulkOptions})).To(Succeed())
+
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		var out, errOut bytes.Buffer
+		renderer := bufferRendererFactory{RendererOptions: rendererOptions, buf: &out}
+		Expect(RunDelete(canceledCtx, server, renderer, sourcesOptions, &errOut, DeleteOptions{BulkOptions: bulkOptions})).
+			To(MatchError(context.Canceled))
+		Expect(out.String()).To(BeEmpty())
+
+		ifaces, err := c.ListInterfaces(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ifaces.Items).To(HaveLen(1))
+	})
+
+	It("should write errors of referenced objects to the error output", func() {
+		Expect(RunCreate(ctx, server, rendererOptions, sourcesOptions, io.Discard, CreateOptions{BulkOptions: bulkOptions})).To(Succeed())
+
+		var out, errOut bytes.Buffer
+		renderer := bufferRendererFactory{RendererOptions: rendererOptions, buf: &out}
+		refs := []string{"interface/vm1", "interface/vm2"}
+		Expect(RunGetObjects(ctx, server, renderer, &errOut, refs)).
+			To(MatchError(Equal("1 of 2 objects could not be retrieved")))
+		Expect(out.String()).To(Equal("interface/vm1\n"))
+		Expect(errOut.String()).To(HavePrefix("Error getting Interface vm2: "))
+
+		out.Reset()
+		errOut.Reset()
+		renderer = bufferRendererFactory{RendererOptions: &RendererOptions{Output: "name"}, buf: &out}
+		Expect(RunDeleteObjects(ctx, server, renderer, &errOut, []string{"prefix/vm1/10.0.1.0/24", "interface/vm2"})).
+			To(MatchError(Equal("1 of 2 objects failed")))
+		Expect(out.String()).To(Equal("prefix/10.0.1.0/24 deleted\n"))
+		Expect(errOut.String()).To(HavePrefix("Error deleting Interface vm2: "))
+	})
+
+	It("should apply objects and fail for objects that cannot be created", func() {
+		filename := filepath.Join(GinkgoT().TempDir(), "failing.yaml")
+		Expect(os.WriteFile(filename, []byte(fakeObjects+`---
+kind: Prefix
+metadata:
+  interface_id: vm2
+spec:
+  prefix: 10.0.2.0/24
+`), 0o644)).To(Succeed())
+
+		var errOut bytes.Buffer
+		Expect(RunApply(ctx, server, rendererOptions, &SourcesOptions{Filename: []string{filename}}, &errOut, ApplyOptions{})).
+			To(MatchError(Equal("1 of 4 objects failed")))
+		Expect(errOut.String()).To(ContainSubstring("Error applying *api.Prefix vm2/10.0.2.0/24"))
+		Expect(errOut.String()).To(ContainSubstring("apply: 3 succeeded, 1 failed, 0 skipped"))
+
+		By("applying the objects again")
+		errOut.Reset()
+		Expect(RunApply(ctx, server, rendererOptions, sourcesOptions, &errOut, ApplyOptions{})).To(Succeed())
+		Expect(errOut.String()).To(Equal("apply: 3 succeeded, 0 failed, 0 skipped\n"))
+	})
+
+	It("should replace objects with fields changed to their zero value", func() {
+		Expect(RunCreate(ctx, server, rendererOptions, sourcesOptions, io.Discard, CreateOptions{BulkOptions: bulkOptions})).To(Succeed())
+
+		rule := func(priority int) *SourcesOptions {
+			filename := filepath.Join(GinkgoT().TempDir(), "fwrule.yaml")
+			Expect(os.WriteFile(filename, []byte(fmt.Sprintf(`kind: FirewallRule
+metadata:
+  interface_id: vm1
+spec:
+  id: r1
+  direction: Ingress
+  action: Accept
+  priority: %d
+  source_prefix: 0.0.0.0/0
+  destination_prefix: 0.0.0.0/0
+`, priority)), 0o644)).To(Succeed())
+			return &SourcesOptions{Filename: []string{filename}}
+		}
+		Expect(RunApply(ctx, server, rendererOptions, rule(100), io.Discard, ApplyOptions{})).To(Succeed())
+
+		Expect(RunDiff(ctx, server, rule(0))).To(MatchError(Equal("1 of 1 objects differ from dpservice")))
+		var out bytes.Buffer
+		renderer := bufferRendererFactory{RendererOptions: rendererOptions, buf: &out}
+		Expect(RunApply(ctx, server, renderer, rule(0), io.Discard, ApplyOptions{})).To(Succeed())
+		Expect(out.String()).To(Equal("firewallrule/vm1/r1 replaced\n"))
+		Expect(RunDiff(ctx, server, rule(0))).To(Succeed())
+	})
+
+	It("should fail for objects lacking a field of their key", func() {
+		filename := filepath.Join(GinkgoT().TempDir(), "invalid.yaml")
+		Expect(os.WriteFile(filename, []byte(`kind: LoadBalancerTarget
+metadata:
+  loadbalancer_id: lb1
+spec: {}
+`), 0o644)).To(Succeed())
+		invalidSources := &SourcesOptions{Filename: []string{filename}}
+
+		var errOut bytes.Buffer
+		Expect(RunApply(ctx, server, rendererOptions, invalidSources, &errOut, ApplyOptions{})).
+			To(MatchError(Equal("1 of 1 objects failed")))
+		Expect(errOut.String()).To(ContainSubstring("loadbalancer target of lb1 has no target_ip"))
+		Expect(RunDiff(ctx, server, invalidSources)).
+			To(MatchError(ContainSubstring("loadbalancer target of lb1 has no target_ip")))
+
+		By("applying a route without prefix")
+		Expect(os.WriteFile(filename, []byte(`kind: Route
+metadata:
+  vni: 100
+spec:
+  next_hop:
+    vni: 0
+    ip: fc00::1
+`), 0o644)).To(Succeed())
+		errOut.Reset()
+		Expect(RunApply(ctx, server, rendererOptions, invalidSources, &errOut, ApplyOptions{})).
+			To(MatchError(Equal("1 of 1 objects failed")))
+		Expect(errOut.String()).To(ContainSubstring("route of vni 100 has no prefix"))
+		Expect(RunDiff(ctx, server, invalidSources)).
+			To(MatchError(ContainSubstring("route of vni 100 has no prefix")))
+	})
+
+	It("should export objects that are unchanged when applied again", func() {
+		Expect(RunCreate(ctx, server, rendererOptions, sourcesOptions, io.Discard, CreateOptions{BulkOptions: bulkOptions})).To(Succeed())
+
+		var exported bytes.Buffer
+		Expect(RunExport(ctx, server, &exported, ExportOptions{Output: "yaml"})).To(Succeed())
+		Expect(exported.String()).NotTo(ContainSubstring("underlay_route"))
+		filename := filepath.Join(GinkgoT().TempDir(), "export.yaml")
+		Expect(os.WriteFile(filename, exported.Bytes(), 0o644)).To(Succeed())
+
+		var out bytes.Buffer
+		renderer := bufferRendererFactory{RendererOptions: rendererOptions, buf: &out}
+		Expect(RunApply(ctx, server, renderer, &SourcesOptions{Filename: []string{filename}}, io.Discard, ApplyOptions{})).To(Succeed())
+		Expect(out.String()).To(Equal("interface/vm1 unchanged\nprefix/10.0.1.0/24 unchanged\nvirtualip/on interface: vm1 unchanged\n"))
+	})
+
+	It("should serve dpservice on a unix socket", func() {
+		socket := filepath.Join(GinkgoT().TempDir(), "dpservice.sock")
+		serveCtx, cancel := context.WithCancel(ctx)
+		done := make(chan error, 1)
+		go func() { done <- RunFakeServer(serveCtx, io.Discard, FakeServerOptions{Listen: "unix://" + socket}) }()
+		DeferCleanup(func() {
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+		})
+
+		opts := &DPDKClientOptions{Address: "unix://" + socket, ConnectTimeout: 2 * time.Second}
+		var out bytes.Buffer
+		Eventually(func() error {
+			out.Reset()
+			return RunVersion(ctx, opts, &out, &RendererOptions{}, VersionOptions{})
+		}).Should(Succeed())
+		Expect(out.String()).To(ContainSubstring("Service Version: " + fake.ServiceVersion))
+	})
+})
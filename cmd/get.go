@@ -4,36 +4,54 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
 
+	"github.com/ironcore-dev/dpservice-cli/dpdk/client/dynamic"
 	"github.com/spf13/cobra"
 )
 
 func Get(factory DPDKClientFactory) *cobra.Command {
 	rendererOptions := &RendererOptions{Output: "table"}
+	watchOptions := &WatchOptions{}
+	w := newWatcher(factory, rendererOptions, watchOptions)
 
 	cmd := &cobra.Command{
-		Use:  "get [command]",
-		Args: cobra.NoArgs,
-		RunE: SubcommandRequired,
+		Use:     "get [command | <kind>/<key>...]",
+		Example: "dpservice-cli get interface/vm1 fwrule/vm1/r12",
+		Args:    cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return SubcommandRequired(cmd, args)
+			}
+			return RunGetObjects(cmd.Context(), w, w, cmd.ErrOrStderr(), args)
+		},
 	}
 
 	rendererOptions.AddFlags(cmd.PersistentFlags())
+	watchOptions.AddFlags(cmd.PersistentFlags())
 
 	subcommands := []*cobra.Command{
-		GetInterface(factory, rendererOptions),
-		GetVirtualIP(factory, rendererOptions),
-		GetLoadBalancer(factory, rendererOptions),
-		GetNat(factory, rendererOptions),
-		GetFirewallRule(factory, rendererOptions),
-		GetVni(factory, rendererOptions),
-		GetVersion(factory, rendererOptions),
-		GetInit(factory, rendererOptions),
+		GetInterface(w, w),
+		GetVirtualIP(w, w),
+		GetLoadBalancer(w, w),
+		GetNat(w, w),
+		GetFirewallRule(w, w),
+		GetVni(w, w),
+		GetVersion(w, w),
+		GetInit(w, w),
 		// Aliases for list commands
-		GetLoadBalancerPrefix(factory, rendererOptions),
-		GetLoadBalancerTarget(factory, rendererOptions),
-		GetPrefix(factory, rendererOptions),
-		GetRoute(factory, rendererOptions),
+		GetLoadBalancerPrefix(w, w),
+		GetLoadBalancerTarget(w, w),
+		GetPrefix(w, w),
+		GetRoute(w, w),
+	}
+
+	w.Wrap(cmd)
+	for _, subcommand := range subcommands {
+		w.Wrap(subcommand)
 	}
 
 	cmd.Short = fmt.Sprintf("Gets one of %v", CommandNames(subcommands))
@@ -45,3 +63,49 @@ func Get(factory DPDKClientFactory) *cobra.Command {
 
 	return cmd
 }
+
+// RunGetObjects gets the objects referenced as <kind>/<key>, e.g. interface/vm1.
+func RunGetObjects(
+	ctx context.Context,
+	dpdkClientFactory DPDKClientFactory,
+	rendererFactory RendererFactory,
+	errOut io.Writer,
+	refs []string,
+) error {
+	keys, err := ParseObjectRefs(refs)
+	if err != nil {
+		return err
+	}
+
+	client, cleanup, err := dpdkClientFactory.NewClient(ctx)
+	if err != nil {
+		return fmt.Errorf("error creating dpdk client: %w", err)
+	}
+	defer DpdkClose(cleanup)
+
+	dc := dynamic.NewFromStructured(client)
+
+	renderer, err := rendererFactory.NewRenderer("", os.Stdout)
+	if err != nil {
+		return fmt.Errorf("error creating renderer: %w", err)
+	}
+
+	failed := 0
+	for _, key := range keys {
+		obj, err := dc.Get(ctx, key)
+		if err != nil {
+			fmt.Fprintf(errOut, "Error getting %s %s: %v\n", kindForKey(key), key, err)
+			failed++
+			continue
+		}
+
+		if err := renderer.Render(obj); err != nil {
+			return fmt.Errorf("error rendering %s %s: %w", kindForKey(key), key, err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d objects could not be retrieved", failed, len(keys))
+	}
+	return nil
+}
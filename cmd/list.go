@@ -11,6 +11,8 @@ import (
 
 func List(factory DPDKClientFactory) *cobra.Command {
 	rendererOptions := &RendererOptions{Output: "table"}
+	watchOptions := &WatchOptions{}
+	w := newWatcher(factory, rendererOptions, watchOptions)
 
 	cmd := &cobra.Command{
 		Use:  "list [command]",
@@ -19,15 +21,20 @@ func List(factory DPDKClientFactory) *cobra.Command {
 	}
 
 	rendererOptions.AddFlags(cmd.PersistentFlags())
+	watchOptions.AddFlags(cmd.PersistentFlags())
 
 	subcommands := []*cobra.Command{
-		ListFirewallRules(factory, rendererOptions),
-		ListInterfaces(factory, rendererOptions),
-		ListPrefixes(factory, rendererOptions),
-		ListLoadBalancerPrefixes(factory, rendererOptions),
-		ListRoutes(factory, rendererOptions),
-		ListLoadBalancerTargets(factory, rendererOptions),
-		ListNats(factory, rendererOptions),
+		ListFirewallRules(w, w),
+		ListInterfaces(w, w),
+		ListPrefixes(w, w),
+		ListLoadBalancerPrefixes(w, w),
+		ListRoutes(w, w),
+		ListLoadBalancerTargets(w, w),
+		ListNats(w, w),
+	}
+
+	for _, subcommand := range subcommands {
+		w.Wrap(subcommand)
 	}
 
 	cmd.Short = fmt.Sprintf("Lists one of %v", CommandNames(subcommands))
@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+
+	"github.com/ironcore-dev/dpservice-cli/dpdk/client/dynamic"
+	"github.com/ironcore-dev/dpservice-go/api"
+)
+
+// liveLister lists the live objects of a kind. In addition to the parents dpservice knows about,
+// it looks below the parents (VNIs, load balancers, NAT IPs) of hints, which is the only way
+// to find load balancers, as dpservice cannot list them.
+type liveLister struct {
+	dc    dynamic.Client
+	hints []any
+}
+
+func (l *liveLister) List(ctx context.Context, kind string) ([]any, error) {
+	switch kind {
+	case api.InterfaceKind, api.PrefixKind, api.LoadBalancerPrefixKind, api.FirewallRuleKind, api.VirtualIPKind, api.NatKind:
+		return l.dc.List(ctx, kind, dynamic.EmptyKey)
+	case api.RouteKind:
+		vnis, err := l.vnis(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return l.listEach(ctx, kind, vnis)
+	case api.LoadBalancerKind, api.LoadBalancerTargetKind:
+		return l.listEach(ctx, kind, l.loadBalancerKeys())
+	case api.NeighborNatKind:
+		natIPs, err := l.natIPs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return l.listEach(ctx, kind, natIPs)
+	default:
+		// list kinds and kinds without identity (e.g. Vni) cannot be listed
+		return nil, nil
+	}
+}
+
+func (l *liveLister) listEach(ctx context.Context, kind string, parentKeys []dynamic.ObjectKey) ([]any, error) {
+	var res []any
+	for _, parentKey := range parentKeys {
+		objs, err := l.dc.List(ctx, kind, parentKey)
+		if err != nil {
+			return nil, fmt.Errorf("[%s] %w", parentKey, err)
+		}
+		res = append(res, objs...)
+	}
+	return res, nil
+}
+
+// vnis returns the VNIs of all live interfaces and of all hinted interfaces, routes and load balancers.
+func (l *liveLister) vnis(ctx context.Context) ([]dynamic.ObjectKey, error) {
+	ifaces, err := l.dc.List(ctx, api.InterfaceKind, dynamic.EmptyKey)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[uint32]struct{})
+	for _, obj := range append(ifaces, l.hints...) {
+		switch obj := obj.(type) {
+		case *api.Interface:
+			seen[obj.Spec.VNI] = struct{}{}
+		case *api.Route:
+			seen[obj.VNI] = struct{}{}
+		case *api.LoadBalancer:
+			seen[obj.Spec.VNI] = struct{}{}
+		}
+	}
+
+	vnis := make([]uint32, 0, len(seen))
+	for vni := range seen {
+		vnis = append(vnis, vni)
+	}
+	sort.Slice(vnis, func(i, j int) bool { return vnis[i] < vnis[j] })
+
+	keys := make([]dynamic.ObjectKey, len(vnis))
+	for i, vni := range vnis {
+		keys[i] = dynamic.VNIKey{VNI: vni}
+	}
+	return keys, nil
+}
+
+// loadBalancerKeys returns the IDs of the hinted load balancers. The parents of hinted targets are
+// left out, as a load balancer that is only referenced by a target is not managed by the hints.
+func (l *liveLister) loadBalancerKeys() []dynamic.ObjectKey {
+	seen := make(map[string]struct{})
+	var keys []dynamic.ObjectKey
+	for _, obj := range l.hints {
+		lb, ok := obj.(*api.LoadBalancer)
+		if !ok {
+			continue
+		}
+		if _, ok := seen[lb.ID]; !ok {
+			seen[lb.ID] = struct{}{}
+			keys = append(keys, dynamic.LoadBalancerKey{ID: lb.ID})
+		}
+	}
+	return keys
+}
+
+// natIPs returns the NAT IPs of all live and hinted NATs and neighbor NATs.
+func (l *liveLister) natIPs(ctx context.Context) ([]dynamic.ObjectKey, error) {
+	nats, err := l.dc.List(ctx, api.NatKind, dynamic.EmptyKey)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[netip.Addr]struct{})
+	var keys []dynamic.ObjectKey
+	add := func(natIP *netip.Addr) {
+		if natIP == nil || !natIP.IsValid() {
+			return
+		}
+		if _, ok := seen[*natIP]; !ok {
+			seen[*natIP] = struct{}{}
+			keys = append(keys, dynamic.NatIPKey{NatIP: *natIP})
+		}
+	}
+	for _, obj := range append(nats, l.hints...) {
+		switch obj := obj.(type) {
+		case *api.Nat:
+			add(obj.Spec.NatIP)
+		case *api.NeighborNat:
+			add(obj.NatIP)
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ironcore-dev/dpservice-cli/dpdk/client/dynamic"
+	"github.com/ironcore-dev/dpservice-go/api"
+)
+
+// kindAliases maps the aliases of the subcommands to the kinds of the scheme.
+var kindAliases = map[string][]string{
+	api.InterfaceKind:          InterfaceAliases,
+	api.PrefixKind:             PrefixAliases,
+	api.RouteKind:              RouteAliases,
+	api.VirtualIPKind:          VirtualIPAliases,
+	api.LoadBalancerKind:       LoadBalancerAliases,
+	api.LoadBalancerPrefixKind: LoadBalancerPrefixAliases,
+	api.LoadBalancerTargetKind: LoadBalancerTargetAliases,
+	api.NatKind:                NatAliases,
+	api.NeighborNatKind:        NeighborNatAliases,
+	api.FirewallRuleKind:       FirewallRuleAliases,
+}
+
+// KindForAlias returns the kind for a kind name or one of its aliases, ignoring case.
+func KindForAlias(alias string) (string, error) {
+	for kind, aliases := range kindAliases {
+		if strings.EqualFold(alias, kind) {
+			return kind, nil
+		}
+		for _, a := range aliases {
+			if strings.EqualFold(alias, a) {
+				return kind, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unknown kind %q", alias)
+}
+
+// ParseObjectRef parses a <kind>/<key> reference, e.g. interface/vm1 or fwrule/vm1/r12,
+// where key is in the form of dynamic.ObjectKey.String.
+func ParseObjectRef(ref string) (dynamic.ObjectKey, error) {
+	alias, key, ok := strings.Cut(ref, "/")
+	if !ok || key == "" {
+		return nil, fmt.Errorf("invalid object reference %q, expected <kind>/<key>", ref)
+	}
+	kind, err := KindForAlias(alias)
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.ParseObjectKey(kind, key)
+}
+
+func ParseObjectRefs(refs []string) ([]dynamic.ObjectKey, error) {
+	keys := make([]dynamic.ObjectKey, len(refs))
+	for i, ref := range refs {
+		key, err := ParseObjectRef(ref)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = key
+	}
+	return keys, nil
+}
+
+// kindForKey returns the kind of the objects identified by key.
+func kindForKey(key dynamic.ObjectKey) string {
+	switch key.(type) {
+	case dynamic.InterfaceKey:
+		return api.InterfaceKind
+	case dynamic.PrefixKey:
+		return api.PrefixKind
+	case dynamic.RouteKey:
+		return api.RouteKind
+	case dynamic.VirtualIPKey:
+		return api.VirtualIPKind
+	case dynamic.LoadBalancerKey:
+		return api.LoadBalancerKind
+	case dynamic.LoadBalancerPrefixKey:
+		return api.LoadBalancerPrefixKind
+	case dynamic.LoadBalancerTargetKey:
+		return api.LoadBalancerTargetKind
+	case dynamic.NatKey:
+		return api.NatKind
+	case dynamic.NeighborNatKey:
+		return api.NeighborNatKind
+	case dynamic.FirewallRuleKey:
+		return api.FirewallRuleKind
+	default:
+		return ""
+	}
+}
+
+// objectKey returns the key of obj for messages. An object lacking a field of its key still gets a
+// key with the zero value for it, the error is reported where the key is needed to find the object.
+func objectKey(obj any) dynamic.ObjectKey {
+	key, _ := dynamic.ObjectKeyFromObject(obj)
+	return key
+}
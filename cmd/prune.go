@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/ironcore-dev/dpservice-cli/dpdk/client/dynamic"
+	"github.com/ironcore-dev/dpservice-cli/dpdk/runtime"
+	"github.com/ironcore-dev/dpservice-go/client"
+	"github.com/spf13/pflag"
+)
+
+type PruneOptions struct {
+	Prune          bool
+	DryRun         bool
+	PruneAllowlist []string
+}
+
+func (o *PruneOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.Prune, "prune", o.Prune, "Delete live objects that are not present in the given files.")
+	fs.BoolVar(&o.DryRun, "prune-dry-run", o.DryRun, "Only show which objects would be pruned, without deleting them. Requires --prune.")
+	fs.StringSliceVar(&o.PruneAllowlist, "prune-allowlist", o.PruneAllowlist, "Kinds that may be pruned, spelled as in the files, e.g. Interface,Route. Defaults to all kinds.")
+}
+
+func (o *PruneOptions) Validate() error {
+	if o.DryRun && !o.Prune {
+		return fmt.Errorf("--prune-dry-run requires --prune")
+	}
+	for _, kind := range o.PruneAllowlist {
+		if _, err := runtime.DefaultScheme.New(kind); err != nil {
+			return fmt.Errorf("invalid prune allowlist entry: %w", err)
+		}
+	}
+	return nil
+}
+
+func (o *PruneOptions) allows(kind string) bool {
+	if len(o.PruneAllowlist) == 0 {
+		return true
+	}
+	// kinds are matched exactly, as by the scheme in Validate
+	return slices.Contains(o.PruneAllowlist, kind)
+}
+
+// RunPrune deletes every live object of the allowed kinds whose key is not present in desired.
+// Objects that cannot be deleted are reported to errOut and make RunPrune return an error.
+func RunPrune(
+	ctx context.Context,
+	client client.Client,
+	rendererFactory RendererFactory,
+	errOut io.Writer,
+	desired []any,
+	opts PruneOptions,
+) error {
+	dc := dynamic.NewFromStructured(client)
+
+	keep := make(map[dynamic.ObjectKey]struct{}, len(desired))
+	for _, obj := range desired {
+		key, err := dynamic.ObjectKeyFromObject(obj)
+		if err != nil {
+			return fmt.Errorf("error getting key of %T: %w", obj, err)
+		}
+		keep[key] = struct{}{}
+	}
+
+	lister := &liveLister{dc: dc, hints: desired}
+	var candidates []any
+	for _, kind := range runtime.DefaultScheme.Kinds() {
+		if !opts.allows(kind) {
+			continue
+		}
+
+		objs, err := lister.List(ctx, kind)
+		if err != nil {
+			return fmt.Errorf("error listing %s: %w", kind, err)
+		}
+
+		for _, obj := range objs {
+			if _, ok := keep[objectKey(obj)]; !ok {
+				candidates = append(candidates, obj)
+			}
+		}
+	}
+
+	if err := runtime.DefaultScheme.SortForDeletion(candidates); err != nil {
+		return fmt.Errorf("error sorting objects: %w", err)
+	}
+
+	operation := "pruned"
+	if opts.DryRun {
+		operation = "pruned (dry run)"
+	}
+	renderer, err := rendererFactory.NewRenderer(operation, os.Stdout)
+	if err != nil {
+		return fmt.Errorf("error creating renderer: %w", err)
+	}
+
+	failed := 0
+	for _, obj := range candidates {
+		if !opts.DryRun {
+			if _, err := dc.Delete(ctx, obj); err != nil {
+				fmt.Fprintf(errOut, "Error pruning %T %s: %v\n", obj, objectKey(obj), err)
+				failed++
+				continue
+			}
+		}
+
+		if err := renderer.Render(obj); err != nil {
+			return fmt.Errorf("error rendering %T: %w", obj, err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d objects could not be pruned", failed, len(candidates))
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/ironcore-dev/dpservice-cli/cmd"
+	"github.com/ironcore-dev/dpservice-cli/fake"
+	"github.com/ironcore-dev/dpservice-cli/renderer"
+	"github.com/ironcore-dev/dpservice-go/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// bufferRendererFactory renders into a buffer instead of the writer passed by the command.
+type bufferRendererFactory struct {
+	*RendererOptions
+	buf *bytes.Buffer
+}
+
+func (f bufferRendererFactory) NewRenderer(operation string, _ io.Writer) (renderer.Renderer, error) {
+	return f.RendererOptions.NewRenderer(operation, f.buf)
+}
+
+const pruneLiveObjects = `kind: Interface
+metadata:
+  id: vm1
+spec:
+  vni: 100
+  primary_ipv4: 10.0.0.1
+---
+kind: Interface
+metadata:
+  id: vm2
+spec:
+  vni: 100
+  primary_ipv4: 10.0.0.2
+---
+kind: LoadBalancer
+metadata:
+  id: lb1
+spec:
+  vni: 100
+  loadbalanced_ip: 10.0.5.1
+  loadbalanced_ports:
+  - protocol: 6
+    port: 80
+---
+kind: LoadBalancerTarget
+metadata:
+  loadbalancer_id: lb1
+spec:
+  target_ip: fc00::1
+`
+
+const pruneDesiredObjects = `kind: Interface
+metadata:
+  id: vm1
+spec:
+  vni: 100
+  primary_ipv4: 10.0.0.1
+---
+kind: LoadBalancerTarget
+metadata:
+  loadbalancer_id: lb1
+spec:
+  target_ip: fc00::1
+`
+
+var _ = Describe("Prune", func() {
+	var (
+		ctx            = context.Background()
+		server         *fake.Server
+		c              client.Client
+		out            *bytes.Buffer
+		renderer       bufferRendererFactory
+		sourcesOptions *SourcesOptions
+	)
+
+	writeObjects := func(data string) *SourcesOptions {
+		filename := filepath.Join(GinkgoT().TempDir(), "objects.yaml")
+		Expect(os.WriteFile(filename, []byte(data), 0o644)).To(Succeed())
+		return &SourcesOptions{Filename: []string{filename}}
+	}
+
+	BeforeEach(func() {
+		server = fake.NewServer()
+		DeferCleanup(server.Stop)
+
+		var (
+			cleanup func() error
+			err     error
+		)
+		c, cleanup, err = server.NewClient(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(cleanup)
+
+		out = &bytes.Buffer{}
+		renderer = bufferRendererFactory{RendererOptions: &RendererOptions{Output: "name"}, buf: out}
+		Expect(RunApply(ctx, server, renderer, writeObjects(pruneLiveObjects), io.Discard, ApplyOptions{})).To(Succeed())
+		out.Reset()
+
+		sourcesOptions = writeObjects(pruneDesiredObjects)
+	})
+
+	It("should not prune a load balancer that is only the parent of a target in the files", func() {
+		opts := ApplyOptions{PruneOptions: PruneOptions{Prune: true, DryRun: true}}
+		Expect(RunApply(ctx, server, renderer, sourcesOptions, io.Discard, opts)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("interface/vm2 pruned (dry run)"))
+		Expect(out.String()).NotTo(ContainSubstring("loadbalancer/lb1 pruned"))
+
+		ifaces, err := c.ListInterfaces(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ifaces.Items).To(HaveLen(2))
+	})
+
+	It("should delete the objects missing from the files", func() {
+		opts := ApplyOptions{PruneOptions: PruneOptions{Prune: true}}
+		Expect(RunApply(ctx, server, renderer, sourcesOptions, io.Discard, opts)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("interface/vm2 pruned"))
+
+		ifaces, err := c.ListInterfaces(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ifaces.Items).To(HaveLen(1))
+		_, err = c.GetLoadBalancer(ctx, "lb1")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should only prune the kinds of the allowlist", func() {
+		opts := ApplyOptions{PruneOptions: PruneOptions{Prune: true, PruneAllowlist: []string{"Route"}}}
+		Expect(RunApply(ctx, server, renderer, sourcesOptions, io.Discard, opts)).To(Succeed())
+		Expect(out.String()).NotTo(ContainSubstring("pruned"))
+
+		opts.PruneAllowlist = []string{"interface"}
+		Expect(RunApply(ctx, server, renderer, sourcesOptions, io.Discard, opts)).
+			To(MatchError(ContainSubstring("invalid prune allowlist entry")))
+	})
+
+	It("should reject --prune-dry-run without --prune", func() {
+		opts := ApplyOptions{PruneOptions: PruneOptions{DryRun: true}}
+		Expect(RunApply(ctx, server, renderer, sourcesOptions, io.Discard, opts)).
+			To(MatchError("--prune-dry-run requires --prune"))
+	})
+})
@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// RecordedCall is a call to dpservice as written by --record, one JSON object per line.
+type RecordedCall struct {
+	Method   string          `json:"method"`
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response,omitempty"`
+	// Code and Error are the gRPC status of failed calls. dpservice errors are part of the response.
+	Code  string `json:"code,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+func marshalRecordedMessage(v any) (json.RawMessage, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("cannot record %T", v)
+	}
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	// protojson randomizes its whitespace, compact it to get stable files
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// recorder appends every call to a file. The file is truncated before the first call, so a
+// recording covers exactly one run of dpservice-cli.
+type recorder struct {
+	mu        sync.Mutex
+	filename  string
+	truncated bool
+}
+
+func (r *recorder) record(call RecordedCall) error {
+	data, err := json.Marshal(call)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	if !r.truncated {
+		flags |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(r.filename, flags, 0o644)
+	if err != nil {
+		return err
+	}
+	r.truncated = true
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// interceptor records every call after its retries, as seen by the command.
+func (r *recorder) interceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		call := RecordedCall{Method: method}
+		var recordErr error
+		if call.Request, recordErr = marshalRecordedMessage(req); recordErr == nil {
+			if err != nil {
+				st := status.Convert(err)
+				call.Code, call.Error = st.Code().String(), st.Message()
+			} else {
+				call.Response, recordErr = marshalRecordedMessage(reply)
+			}
+		}
+		if recordErr == nil {
+			recordErr = r.record(call)
+		}
+		if recordErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: error recording %s: %v\n", method, recordErr)
+		}
+		return err
+	}
+}
+
+// parseCode parses the name of a gRPC code as returned by codes.Code.String.
+func parseCode(name string) codes.Code {
+	for code := codes.OK; code <= codes.Unauthenticated; code++ {
+		if code.String() == name {
+			return code
+		}
+	}
+	return codes.Unknown
+}
+
+// replayer answers calls with the responses of a recording instead of calling dpservice.
+type replayer struct {
+	mu    sync.Mutex
+	calls []RecordedCall
+	used  []bool
+}
+
+func loadReplayer(filename string) (*replayer, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error opening recording: %w", err)
+	}
+	defer f.Close()
+
+	r := &replayer{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var call RecordedCall
+		if err := json.Unmarshal(scanner.Bytes(), &call); err != nil {
+			return nil, fmt.Errorf("error reading recording %s line %d: %w", filename, line, err)
+		}
+		r.calls = append(r.calls, call)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading recording %s: %w", filename, err)
+	}
+	r.used = make([]bool, len(r.calls))
+	return r, nil
+}
+
+// next returns the first unused recorded call of the method with an equal request. Calls are not
+// replayed in their recorded order, as concurrent calls may have been recorded in a different order.
+func (r *replayer) next(method string, req json.RawMessage) (RecordedCall, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, call := range r.calls {
+		if r.used[i] || call.Method != method || !bytes.Equal(call.Request, req) {
+			continue
+		}
+		r.used[i] = true
+		return call, true
+	}
+	return RecordedCall{}, false
+}
+
+// interceptor serves the calls from the recording. It never calls the invoker, so the connection
+// it is used with is never established.
+func (r *replayer) interceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		data, err := marshalRecordedMessage(req)
+		if err != nil {
+			return status.Errorf(codes.Internal, "error encoding request: %v", err)
+		}
+		call, ok := r.next(method, data)
+		if !ok {
+			return status.Errorf(codes.FailedPrecondition, "no recorded call of %s with this request left to replay: %s", method, data)
+		}
+		if call.Code != "" {
+			return status.Error(parseCode(call.Code), call.Error)
+		}
+		msg, ok := reply.(proto.Message)
+		if !ok {
+			return status.Errorf(codes.Internal, "cannot replay into %T", reply)
+		}
+		if err := protojson.Unmarshal(call.Response, msg); err != nil {
+			return status.Errorf(codes.Internal, "error decoding recorded response of %s: %v", method, err)
+		}
+		return nil
+	}
+}
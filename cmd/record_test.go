@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/ironcore-dev/dpservice-cli/cmd"
+	"github.com/ironcore-dev/dpservice-cli/fake"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Record and replay", func() {
+	var (
+		ctx       = context.Background()
+		recording string
+	)
+
+	BeforeEach(func() {
+		recording = filepath.Join(GinkgoT().TempDir(), "session.jsonl")
+	})
+
+	record := func(run func(opts *DPDKClientOptions)) {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		server := fake.NewServer()
+		go func() {
+			defer GinkgoRecover()
+			_ = server.Serve(lis)
+		}()
+		defer lis.Close()
+
+		run(&DPDKClientOptions{Address: lis.Addr().String(), ConnectTimeout: 2 * time.Second, Record: recording})
+	}
+
+	It("should reproduce the output of a command without dpservice", func() {
+		var recorded bytes.Buffer
+		record(func(opts *DPDKClientOptions) {
+			Expect(RunDoctor(ctx, opts, &recorded, &RendererOptions{Output: "json"})).NotTo(Succeed())
+		})
+
+		data, err := os.ReadFile(recording)
+		Expect(err).NotTo(HaveOccurred())
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		Expect(lines).To(HaveLen(4))
+		var call RecordedCall
+		Expect(json.Unmarshal([]byte(lines[0]), &call)).To(Succeed())
+		Expect(call.Method).To(HaveSuffix("/GetVersion"))
+
+		opts := &DPDKClientOptions{Address: "127.0.0.1:1", Replay: recording}
+		var replayed bytes.Buffer
+		Expect(RunDoctor(ctx, opts, &replayed, &RendererOptions{Output: "json"})).NotTo(Succeed())
+
+		var recordedChecks, replayedChecks []DoctorCheck
+		Expect(json.Unmarshal(recorded.Bytes(), &recordedChecks)).To(Succeed())
+		Expect(json.Unmarshal(replayed.Bytes(), &replayedChecks)).To(Succeed())
+		Expect(replayedChecks).To(HaveLen(len(recordedChecks)))
+		// the connection and version checks contain the latency
+		for i := 2; i < len(recordedChecks); i++ {
+			Expect(replayedChecks[i]).To(Equal(recordedChecks[i]))
+		}
+		Expect(replayedChecks[3].Status).To(Equal(CheckFailed), "dpservice was not initialized")
+	})
+
+	It("should fail calls missing from the recording", func() {
+		record(func(opts *DPDKClientOptions) {
+			Expect(RunVersion(ctx, opts, &bytes.Buffer{}, &RendererOptions{}, VersionOptions{})).To(Succeed())
+		})
+
+		opts := &DPDKClientOptions{Replay: recording}
+		Expect(RunVersion(ctx, opts, &bytes.Buffer{}, &RendererOptions{}, VersionOptions{})).To(Succeed())
+		Expect(RunVersion(ctx, opts, &bytes.Buffer{}, &RendererOptions{}, VersionOptions{})).
+			To(MatchError(ContainSubstring("no recorded call")))
+	})
+
+	It("should fail calls whose request differs from the recording", func() {
+		record(func(opts *DPDKClientOptions) {
+			c, cleanup, err := opts.NewClient(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = cleanup() }()
+			_, err = c.GetInterface(ctx, "vm1")
+			Expect(err).To(HaveOccurred())
+		})
+
+		c, cleanup, err := (&DPDKClientOptions{Replay: recording}).NewClient(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(cleanup)
+		_, err = c.GetInterface(ctx, "vm2")
+		Expect(err).To(MatchError(ContainSubstring("no recorded call of /dpdkironcore.v1.DPDKironcore/GetInterface with this request")))
+		_, err = c.GetInterface(ctx, "vm1")
+		Expect(err).To(MatchError(ContainSubstring("not found")))
+	})
+
+	It("should not record and replay at once", func() {
+		opts := &DPDKClientOptions{Record: recording, Replay: recording}
+		_, _, err := opts.NewClient(ctx)
+		Expect(err).To(MatchError(ContainSubstring("cannot be used together")))
+	})
+})
@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// readMethodPrefixes are the prefixes of the dpservice methods that do not change any state
+// and can therefore be retried whenever they fail with a transient error.
+var readMethodPrefixes = []string{"Get", "List", "Check", "CaptureStatus"}
+
+func isReadMethod(fullMethod string) bool {
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	for _, prefix := range readMethodPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRetryable reports whether a call of method that failed with err may be retried.
+// Reads are retried on unavailability and on timeouts of the single attempt. All other calls,
+// e.g. creates, are only retried if the call never reached a connection, as dpservice might
+// have executed it otherwise.
+func isRetryable(method string, err error, sent bool) bool {
+	switch status.Code(err) {
+	case codes.Unavailable:
+		return !sent || isReadMethod(method)
+	case codes.DeadlineExceeded:
+		return isReadMethod(method)
+	default:
+		return false
+	}
+}
+
+// unaryInterceptor applies --request-timeout to every attempt of a call and retries failed calls
+// up to --retries times, doubling --retry-backoff after each attempt.
+func (o *DPDKClientOptions) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		backoff := o.RetryBackoff
+		for attempt := 0; ; attempt++ {
+			// the peer is only set once the call was sent on a connection
+			var p peer.Peer
+			err := o.invoke(ctx, method, req, reply, cc, invoker, append(opts, grpc.Peer(&p))...)
+			if err == nil || attempt >= o.Retries || ctx.Err() != nil || !isRetryable(method, err, p.Addr != nil) {
+				return err
+			}
+
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+	}
+}
+
+func (o *DPDKClientOptions) invoke(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if o.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.RequestTimeout)
+		defer cancel()
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
@@ -0,0 +1,496 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ironcore-dev/dpservice-go/client"
+	apierrors "github.com/ironcore-dev/dpservice-go/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// sharedClientFactory hands out the same client to all commands, so they share one connection.
+// The connection is made by the first command and kept until Close.
+type sharedClientFactory struct {
+	factory DPDKClientFactory
+
+	mu      sync.Mutex
+	client  client.Client
+	cleanup func() error
+}
+
+func (f *sharedClientFactory) NewClient(ctx context.Context) (client.Client, func() error, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.client == nil {
+		c, cleanup, err := f.factory.NewClient(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		f.client, f.cleanup = c, cleanup
+	}
+	return f.client, func() error { return nil }, nil
+}
+
+func (f *sharedClientFactory) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.client == nil {
+		return nil
+	}
+	f.client = nil
+	return f.cleanup()
+}
+
+// shellContextFlags maps the keys of the use command to the flags they pre-fill.
+var shellContextFlags = map[string]string{
+	"vni":       "vni",
+	"interface": "interface-id",
+	"lb":        "lb-id",
+}
+
+var shellBuiltins = []string{"exit", "history", "quit", "use"}
+
+func Shell(dpdkClientFactory DPDKClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "shell",
+		Short: "Run commands interactively on a single connection to dpservice",
+		Long: `Run commands interactively on a single connection to dpservice. Every line is run like the arguments of
+dpservice-cli, e.g. 'list interfaces'. The global connection flags cannot be changed in the shell.
+
+Besides the commands of dpservice-cli, the shell knows:
+  use <key> <value>  pre-fill the flag of <key> for all following commands, keys are vni, interface and lb
+  use <key>          stop pre-filling the flag of <key>
+  use                print the pre-filled flags
+  history            print the lines entered so far
+  exit, quit         leave the shell, as does Ctrl-D`,
+		Example: "dpservice-cli shell --address=node1:1337",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunShell(
+				cmd.Context(),
+				dpdkClientFactory,
+				cmd.InOrStdin(),
+				cmd.OutOrStdout(),
+			)
+		},
+	}
+	return cmd
+}
+
+func RunShell(ctx context.Context, dpdkClientFactory DPDKClientFactory, in io.Reader, out io.Writer) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	s := newShell(dpdkClientFactory, out)
+	defer DpdkClose(s.factory.Close)
+
+	if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		return s.runTerminal(ctx, f)
+	}
+	return s.runLines(ctx, in)
+}
+
+type shell struct {
+	factory *sharedClientFactory
+	out     io.Writer
+
+	// values are the flag values set with use, by key
+	values  map[string]string
+	history []string
+	// loadBalancerIDs are the load balancers seen in the session, as dpservice cannot list them
+	loadBalancerIDs map[string]struct{}
+}
+
+func newShell(dpdkClientFactory DPDKClientFactory, out io.Writer) *shell {
+	return &shell{
+		factory:         &sharedClientFactory{factory: dpdkClientFactory},
+		out:             out,
+		values:          make(map[string]string),
+		loadBalancerIDs: make(map[string]struct{}),
+	}
+}
+
+// runLines runs the lines of a non-interactive input, e.g. a pipe.
+func (s *shell) runLines(ctx context.Context, in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		exit, err := s.exec(ctx, scanner.Text())
+		if err != nil {
+			printLineError(s.out, err)
+		}
+		if exit {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+func (s *shell) runTerminal(ctx context.Context, f *os.File) error {
+	fd := int(f.Fd())
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{f, s.out}, s.prompt())
+	t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		return s.autoComplete(ctx, t, line, pos)
+	}
+
+	for {
+		if width, height, err := term.GetSize(fd); err == nil {
+			_ = t.SetSize(width, height)
+		}
+		t.SetPrompt(s.prompt())
+
+		// the terminal is only raw while reading, so the output of commands is not mangled
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("error setting up terminal: %w", err)
+		}
+		line, err := t.ReadLine()
+		_ = term.Restore(fd, state)
+		if err == io.EOF {
+			fmt.Fprintln(s.out)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading line: %w", err)
+		}
+
+		// Ctrl-C stops the running command, e.g. a list in watch mode, but not the shell
+		cmdCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+		exit, err := s.exec(cmdCtx, line)
+		stop()
+		if err != nil {
+			printLineError(s.out, err)
+		}
+		if exit {
+			return nil
+		}
+	}
+}
+
+// printLineError prints the error of a line. Errors returned by dpservice were already rendered by
+// the command, which returns just the exit code then.
+func printLineError(w io.Writer, err error) {
+	if err.Error() == strconv.Itoa(apierrors.SERVER_ERROR) {
+		return
+	}
+	fmt.Fprintf(w, "Error: %v\n", err)
+}
+
+func (s *shell) prompt() string {
+	var values []string
+	for _, key := range sortedKeys(s.values) {
+		values = append(values, key+"="+s.values[key])
+	}
+	if len(values) == 0 {
+		return "dpservice> "
+	}
+	return fmt.Sprintf("dpservice [%s]> ", strings.Join(values, " "))
+}
+
+// exec runs a line and reports whether the shell should exit.
+func (s *shell) exec(ctx context.Context, line string) (bool, error) {
+	args, err := splitShellLine(line)
+	if err != nil {
+		return false, err
+	}
+	if len(args) == 0 {
+		return false, nil
+	}
+	s.history = append(s.history, line)
+
+	switch args[0] {
+	case "exit", "quit":
+		return true, nil
+	case "history":
+		for i, line := range s.history {
+			fmt.Fprintf(s.out, "%5d  %s\n", i+1, line)
+		}
+		return false, nil
+	case "use":
+		return false, s.use(args[1:])
+	default:
+		return false, s.run(ctx, args)
+	}
+}
+
+func (s *shell) use(args []string) error {
+	switch len(args) {
+	case 0:
+		for _, key := range sortedKeys(s.values) {
+			fmt.Fprintf(s.out, "%s=%s (--%s)\n", key, s.values[key], shellContextFlags[key])
+		}
+		return nil
+	case 1, 2:
+		if _, ok := shellContextFlags[args[0]]; !ok {
+			return fmt.Errorf("unknown key %q, use one of %v", args[0], sortedKeys(shellContextFlags))
+		}
+		if len(args) == 1 {
+			delete(s.values, args[0])
+			return nil
+		}
+		s.values[args[0]] = args[1]
+		if args[0] == "lb" {
+			s.loadBalancerIDs[args[1]] = struct{}{}
+		}
+		return nil
+	default:
+		return fmt.Errorf("usage: use [<key> [<value>]]")
+	}
+}
+
+// newRoot returns a new command tree for a line, so no flag values are left over from previous lines.
+func (s *shell) newRoot() *cobra.Command {
+	return newLineCommand(s.factory)
+}
+
+// newLineCommand returns a root command running the commands of a line of the shell or a batch script.
+func newLineCommand(dpdkClientFactory DPDKClientFactory) *cobra.Command {
+	rendererOptions := &RendererOptions{}
+	root := &cobra.Command{
+		Use:           "dpservice-cli",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE:          SubcommandRequired,
+	}
+	root.CompletionOptions.DisableDefaultCmd = true
+	rendererOptions.AddFlags(root.PersistentFlags())
+	root.AddCommand(dpserviceCommands(dpdkClientFactory, rendererOptions)...)
+	return root
+}
+
+func (s *shell) run(ctx context.Context, args []string) error {
+	root := s.newRoot()
+	root.SetArgs(args)
+	root.SetOut(s.out)
+	root.SetErr(s.out)
+
+	target, _, err := root.Find(args)
+	if err == nil {
+		// flags given on the line are parsed later and take precedence
+		for key, value := range s.values {
+			if target.Flags().Lookup(shellContextFlags[key]) != nil {
+				if err := target.Flags().Set(shellContextFlags[key], value); err != nil {
+					return fmt.Errorf("error pre-filling --%s: %w", shellContextFlags[key], err)
+				}
+			}
+		}
+	}
+
+	if err := root.ExecuteContext(ctx); err != nil {
+		return err
+	}
+	if target != nil {
+		s.rememberLoadBalancer(target)
+	}
+	return nil
+}
+
+func (s *shell) rememberLoadBalancer(cmd *cobra.Command) {
+	if f := cmd.Flags().Lookup("lb-id"); f != nil && f.Value.String() != "" {
+		s.loadBalancerIDs[f.Value.String()] = struct{}{}
+	}
+	if cmd.Name() == "loadbalancer" && cmd.Parent() != nil && cmd.Parent().Name() != "delete" {
+		if f := cmd.Flags().Lookup("id"); f != nil && f.Value.String() != "" {
+			s.loadBalancerIDs[f.Value.String()] = struct{}{}
+		}
+	}
+}
+
+// completions completes IDs using the shared connection and the load balancers seen in the session.
+func (s *shell) completions() *liveCompletions {
+	return &liveCompletions{
+		factory: func(*cobra.Command) DPDKClientFactory { return s.factory },
+		loadBalancerIDs: func() []string {
+			return sortedKeys(s.loadBalancerIDs)
+		},
+	}
+}
+
+// complete returns the candidates for the last word of line, which is empty if line ends with a space.
+func (s *shell) complete(ctx context.Context, line string) []string {
+	args, _ := splitShellLine(line)
+	partial := ""
+	if len(args) > 0 && !strings.HasSuffix(line, " ") {
+		partial, args = args[len(args)-1], args[:len(args)-1]
+	}
+
+	var candidates []string
+	switch {
+	case len(args) == 0:
+		candidates = append(candidates, shellBuiltins...)
+		candidates = append(candidates, s.cobraCandidates(ctx, args, partial)...)
+	case args[0] == "use" && len(args) == 1:
+		candidates = sortedKeys(shellContextFlags)
+	case args[0] == "use" && len(args) == 2 && args[1] == "interface":
+		candidates = s.completions().list(ctx, nil, listInterfaceIDs)
+	case args[0] == "use" && len(args) == 2 && args[1] == "lb":
+		candidates = sortedKeys(s.loadBalancerIDs)
+	case args[0] == "use":
+	default:
+		candidates = s.cobraCandidates(ctx, args, partial)
+	}
+
+	var res []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, partial) {
+			res = append(res, candidate)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// cobraCandidates asks cobra for the completions of the arguments, like the completion scripts do.
+func (s *shell) cobraCandidates(ctx context.Context, args []string, partial string) []string {
+	root := s.newRoot()
+	s.completions().register(root)
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(io.Discard)
+	root.SetArgs(append(append([]string{cobra.ShellCompRequestCmd}, args...), partial))
+	if err := root.ExecuteContext(ctx); err != nil {
+		return nil
+	}
+
+	// flags given as --flag=value are completed without the flag
+	flagPrefix := ""
+	if i := strings.Index(partial, "="); i > 0 && strings.HasPrefix(partial, "-") {
+		flagPrefix = partial[:i+1]
+	}
+
+	var candidates []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		// the last line is the directive, descriptions are separated by a tab
+		if line == "" || strings.HasPrefix(line, ":") {
+			continue
+		}
+		candidate, _, _ := strings.Cut(line, "\t")
+		if flagPrefix != "" && !strings.HasPrefix(candidate, flagPrefix) {
+			candidate = flagPrefix + candidate
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+// autoComplete completes the word before the cursor. If the candidates have no longer common
+// prefix than the word, they are printed above the prompt.
+func (s *shell) autoComplete(ctx context.Context, t *term.Terminal, line string, pos int) (string, int, bool) {
+	prefix, suffix := line[:pos], line[pos:]
+	candidates := s.complete(ctx, prefix)
+	if len(candidates) == 0 {
+		return "", 0, false
+	}
+
+	partial := ""
+	if args, _ := splitShellLine(prefix); len(args) > 0 && !strings.HasSuffix(prefix, " ") {
+		partial = args[len(args)-1]
+	}
+	completion := commonPrefix(candidates)
+	if len(candidates) == 1 {
+		completion += " "
+	}
+	if len(completion) > len(partial) {
+		newPrefix := prefix[:len(prefix)-len(partial)] + completion
+		return newPrefix + suffix, len(newPrefix), true
+	}
+
+	fmt.Fprintf(t, "%s%s\n%s\n", s.prompt(), line, strings.Join(candidates, "  "))
+	return "", 0, false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, value := range values[1:] {
+		for !strings.HasPrefix(value, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// splitShellLine splits a line into arguments like a POSIX shell, supporting single and double
+// quotes and backslash escapes. Lines starting with # are comments.
+func splitShellLine(line string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inArg = true, true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == '#' && !inArg:
+			return args, nil
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		if inArg {
+			args = append(args, current.String())
+		}
+		return args, fmt.Errorf("unterminated quote or escape")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
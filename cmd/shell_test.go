@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync/atomic"
+
+	. "github.com/ironcore-dev/dpservice-cli/cmd"
+	"github.com/ironcore-dev/dpservice-cli/fake"
+	"github.com/ironcore-dev/dpservice-go/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// countingFactory counts the clients created by a factory.
+type countingFactory struct {
+	DPDKClientFactory
+	clients atomic.Int32
+}
+
+func (f *countingFactory) NewClient(ctx context.Context) (client.Client, func() error, error) {
+	f.clients.Add(1)
+	return f.DPDKClientFactory.NewClient(ctx)
+}
+
+var _ = Describe("Shell", func() {
+	var (
+		ctx     = context.Background()
+		server  *fake.Server
+		factory *countingFactory
+		c       client.Client
+	)
+
+	BeforeEach(func() {
+		server = fake.NewServer()
+		DeferCleanup(server.Stop)
+		factory = &countingFactory{DPDKClientFactory: server}
+
+		var (
+			cleanup func() error
+			err     error
+		)
+		c, cleanup, err = server.NewClient(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(cleanup)
+	})
+
+	runShell := func(lines ...string) string {
+		var out bytes.Buffer
+		Expect(RunShell(ctx, factory, strings.NewReader(strings.Join(lines, "\n")), &out)).To(Succeed())
+		return out.String()
+	}
+
+	It("should run commands on one connection and pre-fill flags with use", func() {
+		out := runShell(
+			"use vni 100",
+			"create interface --id=vm1 --ipv4=10.0.0.1 --device=net_tap1",
+			"use interface vm1",
+			"create prefix --prefix=10.0.1.0/24",
+			"create prefix --prefix 10.0.2.0/24 --interface-id 'vm2'",
+			"use",
+		)
+		Expect(out).To(ContainSubstring("interface=vm1 (--interface-id)\nvni=100 (--vni)\n"))
+		Expect(out).NotTo(ContainSubstring("Error: 2"), "server errors are rendered by the command")
+		Expect(factory.clients.Load()).To(Equal(int32(1)))
+
+		iface, err := c.GetInterface(ctx, "vm1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(iface.Spec.VNI).To(Equal(uint32(100)))
+		prefixes, err := c.ListPrefixes(ctx, "vm1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(prefixes.Items).To(HaveLen(1))
+		Expect(prefixes.Items[0].Spec.Prefix.String()).To(Equal("10.0.1.0/24"))
+	})
+
+	It("should handle builtins, comments and errors", func() {
+		out := runShell(
+			"# a comment",
+			"use cluster a",
+			"create prefix --prefix=10.0.1.0/24",
+			"get nothing",
+			"history",
+			"exit",
+			"use vni 100",
+		)
+		Expect(out).To(ContainSubstring(`Error: unknown key "cluster"`))
+		Expect(out).To(ContainSubstring(`Error: required flag(s) "interface-id" not set`))
+		Expect(out).To(ContainSubstring("    1  use cluster a\n    2  create prefix"))
+		Expect(out).NotTo(ContainSubstring("use vni 100"))
+		Expect(factory.clients.Load()).To(BeZero())
+	})
+})
@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"io"
+
+	"github.com/ironcore-dev/dpservice-cli/dpdk/runtime"
+	"github.com/ironcore-dev/dpservice-go/errors"
+)
+
+const (
+	resultSucceeded  = "succeeded"
+	resultFailed     = "failed"
+	resultSkipped    = "skipped"
+	resultRolledBack = "rolled back"
+	// resultRollbackFailed is an object that was created, but could not be deleted again by the rollback
+	resultRollbackFailed = "rollback failed"
+)
+
+// BulkSummary is the outcome of creating or deleting objects from files. RollbackFailed counts
+// the created objects that are left behind, as the rollback of --atomic could not delete them.
+type BulkSummary struct {
+	Operation      string             `json:"operation"`
+	Succeeded      int                `json:"succeeded"`
+	Failed         int                `json:"failed"`
+	Skipped        int                `json:"skipped"`
+	RolledBack     int                `json:"rolledBack,omitempty"`
+	RollbackFailed int                `json:"rollbackFailed,omitempty"`
+	Objects        []BulkObjectResult `json:"objects"`
+}
+
+// BulkObjectResult is the outcome for a single object. Code and Message are set
+// for failed objects, Code only if the failure was reported by dpservice.
+type BulkObjectResult struct {
+	Kind    string `json:"kind"`
+	Key     string `json:"key"`
+	Result  string `json:"result"`
+	Code    uint32 `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+
+	obj any
+}
+
+func (s *BulkSummary) add(result bulkResult) {
+	kind, _ := runtime.DefaultScheme.KindFor(result.Obj)
+	r := BulkObjectResult{
+		Kind: kind,
+		Key:  objectKey(result.Obj).String(),
+		obj:  result.Obj,
+	}
+
+	switch {
+	case result.Skipped:
+		r.Result = resultSkipped
+		s.Skipped++
+	case result.Err != nil:
+		r.Result = resultFailed
+		r.Message = result.Err.Error()
+		var statusErr *errors.StatusError
+		if goerrors.As(result.Err, &statusErr) {
+			r.Code = statusErr.ErrorCode()
+			r.Message = statusErr.Message()
+		}
+		s.Failed++
+	default:
+		r.Result = resultSucceeded
+		s.Succeeded++
+	}
+
+	s.Objects = append(s.Objects, r)
+}
+
+// Err returns an error if any object failed, so the command exits non-zero.
+func (s *BulkSummary) Err() error {
+	if s.Failed == 0 {
+		return nil
+	}
+	if s.RollbackFailed > 0 {
+		return fmt.Errorf("%d of %d objects failed, rolled back %d objects, rollback failed for %d objects", s.Failed, len(s.Objects), s.RolledBack, s.RollbackFailed)
+	}
+	if s.RolledBack > 0 {
+		return fmt.Errorf("%d of %d objects failed, rolled back %d objects", s.Failed, len(s.Objects), s.RolledBack)
+	}
+	return fmt.Errorf("%d of %d objects failed", s.Failed, len(s.Objects))
+}
+
+// Write writes the summary in the given format. The text format only contains the counts,
+// as failed objects are already reported while processing them.
+func (s *BulkSummary) Write(w io.Writer, format string) error {
+	switch format {
+	case "json":
+		return json.NewEncoder(w).Encode(s)
+	default:
+		msg := fmt.Sprintf("%s: %d succeeded, %d failed, %d skipped", s.Operation, s.Succeeded, s.Failed, s.Skipped)
+		if s.RolledBack > 0 {
+			msg += fmt.Sprintf(", %d rolled back", s.RolledBack)
+		}
+		if s.RollbackFailed > 0 {
+			msg += fmt.Sprintf(", %d rollback failed", s.RollbackFailed)
+		}
+		_, err := fmt.Fprintln(w, msg)
+		return err
+	}
+}
@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// TraceVerbosity is the verbosity from which on every call to dpservice is logged.
+const TraceVerbosity = 4
+
+// traceInterceptor logs the method, latency, status, request and response of every call to w.
+// Retried calls are logged once per attempt.
+func traceInterceptor(w io.Writer) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		latency := time.Since(start)
+
+		st := status.Convert(err)
+		fmt.Fprintf(w, "grpc %s status=%s latency=%s\n", method, st.Code(), latency)
+		fmt.Fprintf(w, "  request: %s\n", traceMessage(req))
+		if err != nil {
+			fmt.Fprintf(w, "  error: %s\n", st.Message())
+		} else {
+			fmt.Fprintf(w, "  response: %s\n", traceMessage(reply))
+		}
+		return err
+	}
+}
+
+func traceMessage(v any) string {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Sprintf("%v", v)
+	}
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return fmt.Sprintf("<error encoding %T: %v>", v, err)
+	}
+	return string(data)
+}
@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ironcore-dev/dpservice-cli/util"
+	"github.com/ironcore-dev/dpservice-go/api"
+	"github.com/ironcore-dev/dpservice-go/client"
+	dpdkproto "github.com/ironcore-dev/dpservice-go/proto"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// ClientProtocol is the version of the dpservice protocol the CLI was built with.
+var ClientProtocol = strings.TrimSpace(dpdkproto.GeneratedFrom)
+
+// VersionInfo contains the versions of dpservice-cli and of the dpservice it is connected to.
+type VersionInfo struct {
+	Client  ComponentVersion  `json:"client"`
+	Service *ComponentVersion `json:"service,omitempty"`
+	// Compatible reports whether the protocol versions of client and service are compatible.
+	Compatible *bool `json:"compatible,omitempty"`
+}
+
+type ComponentVersion struct {
+	Version  string `json:"version"`
+	Protocol string `json:"protocol"`
+}
+
+// CompatibleProtocols reports whether the client and service protocol versions are compatible,
+// which is the case if their major and minor versions match. Versions that are not of the form
+// v<major>.<minor>[.<patch>] have to be equal.
+func CompatibleProtocols(clientProtocol, serviceProtocol string) bool {
+	clientMajorMinor, ok := protocolMajorMinor(clientProtocol)
+	if !ok {
+		return clientProtocol == serviceProtocol
+	}
+	serviceMajorMinor, ok := protocolMajorMinor(serviceProtocol)
+	return ok && clientMajorMinor == serviceMajorMinor
+}
+
+func protocolMajorMinor(version string) (string, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return "", false
+	}
+	for _, part := range parts[:2] {
+		if _, err := strconv.Atoi(part); err != nil {
+			return "", false
+		}
+	}
+	return parts[0] + "." + parts[1], true
+}
+
+// getServiceVersion calls GetVersion, which also lets dpservice log the client name and version.
+func getServiceVersion(ctx context.Context, c client.Client) (*api.Version, error) {
+	version, err := c.GetVersion(ctx, &api.Version{
+		TypeMeta: api.TypeMeta{Kind: api.VersionKind},
+		VersionMeta: api.VersionMeta{
+			ClientName:    "dpservice-cli",
+			ClientVersion: util.BuildVersion,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting version: %w", err)
+	}
+	return version, nil
+}
+
+// checkVersion compares the protocol version of dpservice with ClientProtocol. Incompatible or
+// unknown versions are an error with --strict-version and a warning on stderr otherwise.
+func (o *DPDKClientOptions) checkVersion(ctx context.Context, c client.Client) error {
+	version, err := getServiceVersion(ctx, c)
+	if err == nil && !CompatibleProtocols(ClientProtocol, version.Spec.ServiceProtocol) {
+		err = fmt.Errorf("dpservice protocol %s is not compatible with protocol %s of dpservice-cli", version.Spec.ServiceProtocol, ClientProtocol)
+	}
+	if err == nil {
+		return nil
+	}
+	if o.StrictVersion {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	return nil
+}
+
+func Version(dpdkClientFactory DPDKClientFactory, rendererOptions *RendererOptions) *cobra.Command {
+	var (
+		opts VersionOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:     "version",
+		Short:   "Print the versions of dpservice-cli and dpservice",
+		Example: "dpservice-cli version -o json",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunVersion(
+				cmd.Context(),
+				dpdkClientFactory,
+				cmd.OutOrStdout(),
+				rendererOptions,
+				opts,
+			)
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+type VersionOptions struct {
+	ClientOnly bool
+}
+
+func (o *VersionOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.ClientOnly, "client", o.ClientOnly, "Only print the version of dpservice-cli without connecting to dpservice.")
+}
+
+func RunVersion(
+	ctx context.Context,
+	dpdkClientFactory DPDKClientFactory,
+	w io.Writer,
+	rendererOptions *RendererOptions,
+	opts VersionOptions,
+) error {
+	info := VersionInfo{
+		Client: ComponentVersion{Version: util.BuildVersion, Protocol: ClientProtocol},
+	}
+
+	var serviceErr error
+	if !opts.ClientOnly {
+		serviceErr = func() error {
+			client, cleanup, err := dpdkClientFactory.NewClient(ctx)
+			if err != nil {
+				return fmt.Errorf("error creating dpdk client: %w", err)
+			}
+			defer DpdkClose(cleanup)
+
+			version, err := getServiceVersion(ctx, client)
+			if err != nil {
+				return err
+			}
+			compatible := CompatibleProtocols(ClientProtocol, version.Spec.ServiceProtocol)
+			info.Service = &ComponentVersion{Version: version.Spec.ServiceVersion, Protocol: version.Spec.ServiceProtocol}
+			info.Compatible = &compatible
+			return nil
+		}()
+	}
+
+	if err := writeVersionInfo(w, rendererOptions, info); err != nil {
+		return err
+	}
+	return serviceErr
+}
+
+func writeVersionInfo(w io.Writer, rendererOptions *RendererOptions, info VersionInfo) error {
+	switch rendererOptions.Output {
+	case "json", "yaml":
+		renderer, err := rendererOptions.NewRenderer("", w)
+		if err != nil {
+			return fmt.Errorf("error creating renderer: %w", err)
+		}
+		return renderer.Render(info)
+	default:
+		fmt.Fprintf(w, "Client Version: %s\n", info.Client.Version)
+		fmt.Fprintf(w, "Client Protocol: %s\n", info.Client.Protocol)
+		if info.Service != nil {
+			fmt.Fprintf(w, "Service Version: %s\n", info.Service.Version)
+			fmt.Fprintf(w, "Service Protocol: %s\n", info.Service.Protocol)
+			if !*info.Compatible {
+				fmt.Fprintln(w, "Warning: the protocol versions are not compatible")
+			}
+		}
+		return nil
+	}
+}
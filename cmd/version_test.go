@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"time"
+
+	. "github.com/ironcore-dev/dpservice-cli/cmd"
+	"github.com/ironcore-dev/dpservice-cli/util"
+	dpdkproto "github.com/ironcore-dev/dpservice-go/proto"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+)
+
+var _ = Describe("Version", func() {
+	var opts *DPDKClientOptions
+
+	startServer := func(protocol string) {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		s := grpc.NewServer()
+		dpdkproto.RegisterDPDKironcoreServer(s, healthyServer{protocol: protocol})
+		go func() {
+			defer GinkgoRecover()
+			_ = s.Serve(lis)
+		}()
+		DeferCleanup(s.Stop)
+		opts.Address = lis.Addr().String()
+	}
+
+	newClient := func() error {
+		_, cleanup, err := opts.NewClient(context.Background())
+		if err == nil {
+			DeferCleanup(cleanup)
+		}
+		return err
+	}
+
+	BeforeEach(func() {
+		opts = &DPDKClientOptions{ConnectTimeout: 2 * time.Second}
+	})
+
+	DescribeTable("CompatibleProtocols",
+		func(client, service string, compatible bool) {
+			Expect(CompatibleProtocols(client, service)).To(Equal(compatible))
+		},
+		Entry("equal", "v0.3.0", "v0.3.0", true),
+		Entry("differing patch", "v0.3.0", "v0.3.2", true),
+		Entry("differing minor", "v0.3.0", "v0.4.0", false),
+		Entry("differing major", "v1.3.0", "v2.3.0", false),
+		Entry("pre-release", "v0.3.0", "v0.3.1-2-gabcdef", true),
+		Entry("unknown service", "v0.3.0", "", false),
+		Entry("unparsable equal", "main", "main", true),
+	)
+
+	It("should only warn about incompatible versions by default", func() {
+		startServer("v0.0.1")
+		opts.CheckVersion = true
+		Expect(newClient()).To(Succeed())
+	})
+
+	It("should fail for incompatible versions with --strict-version", func() {
+		startServer("v0.0.1")
+		opts.StrictVersion = true
+		Expect(newClient()).To(MatchError(ContainSubstring("dpservice protocol v0.0.1 is not compatible")))
+	})
+
+	It("should accept compatible versions with --strict-version", func() {
+		startServer(ClientProtocol)
+		opts.StrictVersion = true
+		Expect(newClient()).To(Succeed())
+	})
+
+	It("should print client and service versions", func() {
+		startServer("v0.0.1")
+
+		var out bytes.Buffer
+		Expect(RunVersion(context.Background(), opts, &out, &RendererOptions{Output: "json"}, VersionOptions{})).To(Succeed())
+
+		var info VersionInfo
+		Expect(json.Unmarshal(out.Bytes(), &info)).To(Succeed())
+		Expect(info.Client.Protocol).To(Equal(ClientProtocol))
+		Expect(info.Service).To(Equal(&ComponentVersion{Version: "v1.0.0", Protocol: "v0.0.1"}))
+		Expect(info.Compatible).To(HaveValue(BeFalse()))
+	})
+
+	It("should only print the client version with --client", func() {
+		opts.Address = "127.0.0.1:1"
+
+		var out bytes.Buffer
+		Expect(RunVersion(context.Background(), opts, &out, &RendererOptions{}, VersionOptions{ClientOnly: true})).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("Client Protocol: " + ClientProtocol))
+		Expect(out.String()).NotTo(ContainSubstring("Service"))
+	})
+
+	It("should keep -v as the shorthand of --version and use --v for the verbosity", func() {
+		buildVersion := util.BuildVersion
+		util.BuildVersion = "v1.2.3"
+		DeferCleanup(func() { util.BuildVersion = buildVersion })
+
+		var out bytes.Buffer
+		cmd := Command()
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"-v"})
+		Expect(cmd.Execute()).To(Succeed())
+		Expect(out.String()).To(Equal("dpservice-cli version v1.2.3\n"))
+
+		cmd = Command()
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"version", "--client", "--v=4"})
+		Expect(cmd.Execute()).To(Succeed())
+		Expect(cmd.PersistentFlags().Lookup("v").Value.String()).To(Equal("4"))
+	})
+})
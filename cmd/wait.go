@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ironcore-dev/dpservice-cli/dpdk/client/dynamic"
+	"github.com/ironcore-dev/dpservice-cli/util"
+	"github.com/ironcore-dev/dpservice-go/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func Wait(dpdkClientFactory DPDKClientFactory) *cobra.Command {
+	var (
+		opts WaitOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "wait <--for> [<kind>/<key>...]",
+		Short: "Wait for a condition on dpservice or on objects",
+		Long: `Wait for a condition on dpservice or on objects. Supported conditions are:
+  initialized       dpservice is initialized
+  exists            the given objects exist
+  deleted           the given objects do not exist
+  capture=active    packet capturing is active
+  capture=inactive  packet capturing is not active`,
+		Example: "dpservice-cli wait --for=exists interface/vm1 --timeout=1m",
+		Args:    cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunWait(
+				cmd.Context(),
+				dpdkClientFactory,
+				cmd.OutOrStdout(),
+				args,
+				opts,
+			)
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+
+	util.Must(opts.MarkRequiredFlags(cmd))
+
+	return cmd
+}
+
+type WaitOptions struct {
+	For      string
+	Timeout  time.Duration
+	Interval time.Duration
+}
+
+func (o *WaitOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.For, "for", o.For, "Condition to wait for. [initialized|exists|deleted|capture=active|capture=inactive]")
+	fs.DurationVar(&o.Timeout, "timeout", 30*time.Second, "Time to wait before giving up.")
+	fs.DurationVar(&o.Interval, "interval", time.Second, "Interval between checks of the condition.")
+}
+
+func (o *WaitOptions) MarkRequiredFlags(cmd *cobra.Command) error {
+	for _, name := range []string{"for"} {
+		if err := cmd.MarkFlagRequired(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitCondition checks the condition once and returns whether it is met. Errors are treated as
+// transient, the condition is checked again until the timeout expires.
+type waitCondition func(ctx context.Context, client client.Client) (bool, error)
+
+func RunWait(
+	ctx context.Context,
+	dpdkClientFactory DPDKClientFactory,
+	w io.Writer,
+	refs []string,
+	opts WaitOptions,
+) error {
+	if opts.Interval <= 0 {
+		return fmt.Errorf("interval must be positive, got %s", opts.Interval)
+	}
+	condition, met, err := parseWaitCondition(opts.For, refs)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	var (
+		c       client.Client
+		cleanup func() error
+		lastErr error
+	)
+	defer func() {
+		if cleanup != nil {
+			DpdkClose(cleanup)
+		}
+	}()
+	for {
+		var ok bool
+		if c == nil {
+			// dpservice may not accept connections yet, e.g. while it is starting
+			c, cleanup, err = dpdkClientFactory.NewClient(ctx)
+			if err != nil {
+				c, cleanup, err = nil, nil, fmt.Errorf("error creating dpdk client: %w", err)
+			}
+		}
+		if c != nil {
+			ok, err = condition(ctx, c)
+		}
+		if ok {
+			fmt.Fprintln(w, met)
+			return nil
+		}
+		if err != nil && ctx.Err() == nil {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return fmt.Errorf("timed out after %s waiting for %s, last error: %w", opts.Timeout, opts.For, lastErr)
+			}
+			return fmt.Errorf("timed out after %s waiting for %s", opts.Timeout, opts.For)
+		case <-time.After(opts.Interval):
+		}
+	}
+}
+
+// parseWaitCondition returns the condition for --for and the message to print once it is met.
+func parseWaitCondition(name string, refs []string) (waitCondition, string, error) {
+	switch name {
+	case "initialized", "capture=active", "capture=inactive":
+		if len(refs) > 0 {
+			return nil, "", fmt.Errorf("condition %s does not take objects", name)
+		}
+	case "exists", "deleted":
+		if len(refs) == 0 {
+			return nil, "", fmt.Errorf("condition %s requires at least one object", name)
+		}
+	default:
+		return nil, "", fmt.Errorf("unsupported condition %q", name)
+	}
+
+	switch name {
+	case "initialized":
+		return func(ctx context.Context, client client.Client) (bool, error) {
+			// dpservice reports an error status until it is initialized
+			if _, err := client.CheckInitialized(ctx); err != nil {
+				return false, fmt.Errorf("error checking initialization: %w", err)
+			}
+			return true, nil
+		}, "dpservice initialized", nil
+	case "capture=active", "capture=inactive":
+		active := name == "capture=active"
+		return func(ctx context.Context, client client.Client) (bool, error) {
+			capture, err := client.CaptureStatus(ctx)
+			if err != nil {
+				return false, fmt.Errorf("error getting capture status: %w", err)
+			}
+			return capture.Spec.OperationStatus == active, nil
+		}, name, nil
+	default:
+		keys, err := ParseObjectRefs(refs)
+		if err != nil {
+			return nil, "", err
+		}
+		exists := name == "exists"
+		return func(ctx context.Context, client client.Client) (bool, error) {
+			dc := dynamic.NewFromStructured(client)
+			for _, key := range keys {
+				_, err := dc.Get(ctx, key)
+				if err != nil && !dynamic.IsNotFound(err) {
+					return false, fmt.Errorf("error getting %s %s: %w", kindForKey(key), key, err)
+				}
+				if (err == nil) != exists {
+					return false, nil
+				}
+			}
+			return true, nil
+		}, fmt.Sprintf("%s %s", strings.Join(refs, " "), name), nil
+	}
+}
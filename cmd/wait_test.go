@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/netip"
+	"sync/atomic"
+	"time"
+
+	. "github.com/ironcore-dev/dpservice-cli/cmd"
+	"github.com/ironcore-dev/dpservice-cli/fake"
+	"github.com/ironcore-dev/dpservice-go/api"
+	"github.com/ironcore-dev/dpservice-go/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// flakyFactory hands out clients of a fake server whose GetInterface fails until failures is used up.
+type flakyFactory struct {
+	*fake.Server
+	failures *atomic.Int32
+}
+
+func (f flakyFactory) NewClient(ctx context.Context) (client.Client, func() error, error) {
+	c, cleanup, err := f.Server.NewClient(ctx)
+	return flakyClient{c, f.failures}, cleanup, err
+}
+
+type flakyClient struct {
+	client.Client
+	failures *atomic.Int32
+}
+
+func (c flakyClient) GetInterface(ctx context.Context, id string, ignoredErrors ...[]uint32) (*api.Interface, error) {
+	if c.failures.Add(-1) >= 0 {
+		return nil, status.Error(codes.Unavailable, "dpservice is restarting")
+	}
+	return c.Client.GetInterface(ctx, id, ignoredErrors...)
+}
+
+// startingFactory fails to create clients of a fake server until failures is used up,
+// as if dpservice did not accept connections yet.
+type startingFactory struct {
+	*fake.Server
+	failures *atomic.Int32
+}
+
+func (f startingFactory) NewClient(ctx context.Context) (client.Client, func() error, error) {
+	if f.failures.Add(-1) >= 0 {
+		return nil, nil, fmt.Errorf("connection refused")
+	}
+	return f.Server.NewClient(ctx)
+}
+
+var _ = Describe("Wait", func() {
+	var (
+		ctx    = context.Background()
+		server *fake.Server
+		c      client.Client
+		opts   WaitOptions
+	)
+
+	createInterface := func(id string) {
+		ipv4 := netip.MustParseAddr("10.0.0.1")
+		_, err := c.CreateInterface(ctx, &api.Interface{
+			InterfaceMeta: api.InterfaceMeta{ID: id},
+			Spec:          api.InterfaceSpec{VNI: 100, IPv4: &ipv4},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	// wait runs RunWait in the background and returns its result and output.
+	wait := func(factory DPDKClientFactory, refs ...string) (chan error, *bytes.Buffer) {
+		done := make(chan error, 1)
+		out := &bytes.Buffer{}
+		go func() {
+			defer GinkgoRecover()
+			done <- RunWait(ctx, factory, out, refs, opts)
+		}()
+		return done, out
+	}
+
+	BeforeEach(func() {
+		server = fake.NewServer()
+		DeferCleanup(server.Stop)
+
+		var (
+			cleanup func() error
+			err     error
+		)
+		c, cleanup, err = server.NewClient(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(cleanup)
+
+		opts = WaitOptions{Timeout: 5 * time.Second, Interval: 10 * time.Millisecond}
+	})
+
+	It("should wait for objects to exist", func() {
+		opts.For = "exists"
+		done, out := wait(server, "interface/vm1")
+		Consistently(done, "50ms").ShouldNot(Receive())
+
+		createInterface("vm1")
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(out.String()).To(Equal("interface/vm1 exists\n"))
+	})
+
+	It("should wait for objects to be deleted", func() {
+		createInterface("vm1")
+		opts.For = "deleted"
+		done, out := wait(server, "interface/vm1")
+		Consistently(done, "50ms").ShouldNot(Receive())
+
+		_, err := c.DeleteInterface(ctx, "vm1")
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(out.String()).To(Equal("interface/vm1 deleted\n"))
+	})
+
+	It("should wait for dpservice to be initialized", func() {
+		opts.For = "initialized"
+		done, out := wait(server)
+		Consistently(done, "50ms").ShouldNot(Receive())
+
+		_, err := c.Initialize(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(out.String()).To(Equal("dpservice initialized\n"))
+	})
+
+	It("should wait for dpservice to accept connections", func() {
+		_, err := c.Initialize(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		failures := &atomic.Int32{}
+		failures.Store(3)
+
+		opts.For = "initialized"
+		done, out := wait(startingFactory{server, failures})
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(out.String()).To(Equal("dpservice initialized\n"))
+		Expect(failures.Load()).To(BeNumerically("<", 0))
+	})
+
+	It("should time out if the condition is not met", func() {
+		opts.For, opts.Timeout = "exists", 50*time.Millisecond
+		Expect(RunWait(ctx, server, &bytes.Buffer{}, []string{"interface/vm1"}, opts)).
+			To(MatchError("timed out after 50ms waiting for exists"))
+	})
+
+	It("should keep polling after transient errors", func() {
+		createInterface("vm1")
+		failures := &atomic.Int32{}
+		failures.Store(3)
+
+		opts.For = "exists"
+		done, _ := wait(flakyFactory{server, failures}, "interface/vm1")
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(failures.Load()).To(BeNumerically("<", 0))
+	})
+
+	It("should report the last error on timeout", func() {
+		failures := &atomic.Int32{}
+		failures.Store(1000)
+
+		opts.For, opts.Timeout = "exists", 50*time.Millisecond
+		Expect(RunWait(ctx, flakyFactory{server, failures}, &bytes.Buffer{}, []string{"interface/vm1"}, opts)).
+			To(MatchError(ContainSubstring("timed out after 50ms waiting for exists, last error: error getting Interface vm1")))
+	})
+
+	It("should report why dpservice is not initialized on timeout", func() {
+		opts.For, opts.Timeout = "initialized", 50*time.Millisecond
+		Expect(RunWait(ctx, server, &bytes.Buffer{}, nil, opts)).
+			To(MatchError(ContainSubstring("timed out after 50ms waiting for initialized, last error: error checking initialization")))
+
+		failures := &atomic.Int32{}
+		failures.Store(1000)
+		Expect(RunWait(ctx, startingFactory{server, failures}, &bytes.Buffer{}, nil, opts)).
+			To(MatchError(ContainSubstring("last error: error creating dpdk client: connection refused")))
+	})
+})
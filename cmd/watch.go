@@ -0,0 +1,359 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ironcore-dev/dpservice-cli/renderer"
+	"github.com/ironcore-dev/dpservice-go/api"
+	"github.com/ironcore-dev/dpservice-go/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	WatchEventAdded   = "ADDED"
+	WatchEventRemoved = "REMOVED"
+	WatchEventChanged = "CHANGED"
+)
+
+type WatchOptions struct {
+	Watch    bool
+	Interval time.Duration
+}
+
+func (o *WatchOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVarP(&o.Watch, "watch", "W", o.Watch, "Keep polling and print added, removed and changed objects.")
+	fs.DurationVar(&o.Interval, "interval", 2*time.Second, "Interval between polls in watch mode.")
+}
+
+// WatchEvent is printed for every added, removed or changed object in watch mode.
+type WatchEvent struct {
+	Type   string `json:"type"`
+	Object any    `json:"object"`
+}
+
+// watcher runs get and list subcommands repeatedly in watch mode. It is passed to the subcommands
+// both as their client factory, handing out a single connection for all polls, and as their
+// renderer factory, collecting the rendered objects so only the differences are printed.
+// If watch mode is off, it delegates to the wrapped factories.
+type watcher struct {
+	factory         DPDKClientFactory
+	rendererOptions *RendererOptions
+	opts            *WatchOptions
+
+	client  client.Client
+	cleanup func() error
+
+	current  []watchedObject
+	previous []watchedObject
+	// headers and widths are the header and column widths of the table printed so far
+	headers []any
+	widths  []int
+}
+
+type watchedObject struct {
+	id   string
+	obj  any
+	data []byte
+}
+
+func newWatcher(factory DPDKClientFactory, rendererOptions *RendererOptions, opts *WatchOptions) *watcher {
+	return &watcher{factory: factory, rendererOptions: rendererOptions, opts: opts}
+}
+
+// Wrap makes cmd poll in watch mode. cmd has to be created with w as its factories.
+func (w *watcher) Wrap(cmd *cobra.Command) {
+	runE := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if !w.opts.Watch {
+			return runE(cmd, args)
+		}
+		return w.run(cmd.Context(), cmd.OutOrStdout(), cmd.ErrOrStderr(), func() error {
+			return runE(cmd, args)
+		})
+	}
+}
+
+func (w *watcher) run(ctx context.Context, out, errOut io.Writer, poll func() error) error {
+	if w.opts.Interval <= 0 {
+		return fmt.Errorf("interval must be positive, got %s", w.opts.Interval)
+	}
+	defer func() {
+		if w.cleanup != nil {
+			DpdkClose(w.cleanup)
+		}
+	}()
+
+	for i := 0; ; i++ {
+		w.current = nil
+		if err := poll(); err != nil {
+			// the first poll fails for invalid arguments as well, so give up
+			if i == 0 {
+				return err
+			}
+			fmt.Fprintf(errOut, "Error polling: %v\n", err)
+		} else if err := w.emit(out); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(w.opts.Interval):
+		}
+	}
+}
+
+func (w *watcher) NewClient(ctx context.Context) (client.Client, func() error, error) {
+	if !w.opts.Watch {
+		return w.factory.NewClient(ctx)
+	}
+	if w.client == nil {
+		c, cleanup, err := w.factory.NewClient(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		w.client, w.cleanup = c, cleanup
+	}
+	return w.client, func() error { return nil }, nil
+}
+
+func (w *watcher) NewRenderer(operation string, out io.Writer) (renderer.Renderer, error) {
+	if !w.opts.Watch {
+		return w.rendererOptions.NewRenderer(operation, out)
+	}
+	return watchRecorder{w}, nil
+}
+
+func (w *watcher) RenderObject(operation string, out io.Writer, obj api.Object) error {
+	if !w.opts.Watch {
+		return w.rendererOptions.RenderObject(operation, out, obj)
+	}
+	return w.record(obj)
+}
+
+func (w *watcher) RenderList(operation string, out io.Writer, list api.List) error {
+	if !w.opts.Watch {
+		return w.rendererOptions.RenderList(operation, out, list)
+	}
+	return w.record(list)
+}
+
+func (w *watcher) GetWide() bool {
+	return w.rendererOptions.GetWide()
+}
+
+type watchRecorder struct {
+	w *watcher
+}
+
+func (r watchRecorder) Render(v any) error {
+	return r.w.record(v)
+}
+
+func (w *watcher) record(v any) error {
+	objs := []any{v}
+	if list, ok := v.(api.List); ok {
+		objs = objs[:0]
+		for _, item := range list.GetItems() {
+			objs = append(objs, item)
+		}
+	}
+
+	for _, obj := range objs {
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("error encoding %T: %w", obj, err)
+		}
+		w.current = append(w.current, watchedObject{id: watchID(obj), obj: obj, data: data})
+	}
+	return nil
+}
+
+// watchID identifies obj across polls.
+func watchID(obj any) string {
+	id := fmt.Sprintf("%T/%s", obj, objectKey(obj))
+	// NATs listed by NAT IP include neighbor NATs, which have no interface
+	if nat, ok := obj.(*api.Nat); ok {
+		id += fmt.Sprintf("/%v/%d/%d-%d", nat.Spec.NatIP, nat.Spec.Vni, nat.Spec.MinPort, nat.Spec.MaxPort)
+	}
+	return id
+}
+
+func (w *watcher) emit(out io.Writer) error {
+	previous := make(map[string]watchedObject, len(w.previous))
+	for _, obj := range w.previous {
+		previous[obj.id] = obj
+	}
+	current := make(map[string]watchedObject, len(w.current))
+	for _, obj := range w.current {
+		current[obj.id] = obj
+	}
+
+	var events []WatchEvent
+	for _, obj := range w.current {
+		prev, ok := previous[obj.id]
+		switch {
+		case !ok:
+			events = append(events, WatchEvent{Type: WatchEventAdded, Object: obj.obj})
+		case string(prev.data) != string(obj.data):
+			events = append(events, WatchEvent{Type: WatchEventChanged, Object: obj.obj})
+		}
+	}
+	for _, prev := range w.previous {
+		if _, ok := current[prev.id]; !ok {
+			events = append(events, WatchEvent{Type: WatchEventRemoved, Object: prev.obj})
+		}
+	}
+	w.previous = w.current
+
+	return w.print(out, events)
+}
+
+func (w *watcher) print(out io.Writer, events []WatchEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	switch w.rendererOptions.Output {
+	case "json":
+		enc := json.NewEncoder(out)
+		for _, event := range events {
+			if err := enc.Encode(event); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "yaml":
+		for _, event := range events {
+			if _, err := fmt.Fprintln(out, "---"); err != nil {
+				return err
+			}
+			if err := renderer.NewYAML(out).Render(event); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "name":
+		for _, event := range events {
+			if err := renderer.NewName(out, strings.ToLower(event.Type)).Render(event.Object); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return w.printTable(out, events)
+	}
+}
+
+// tableSegment are the rows of a poll below the same header.
+type tableSegment struct {
+	headers   []string
+	newHeader bool
+	rows      [][]string
+}
+
+// printTable prints one row per event, prefixed with the event type. The rows of consecutive polls
+// form a single table: the header is only printed again if it changes or a column has to be widened,
+// and all rows are padded to the column widths of the header.
+func (w *watcher) printTable(out io.Writer, events []WatchEvent) error {
+	renderer.DefaultTableConverter.SetWide(w.rendererOptions.Wide)
+
+	var segments []*tableSegment
+	for _, event := range events {
+		data, err := renderer.DefaultTableConverter.ConvertToTable(event.Object)
+		if err != nil {
+			return err
+		}
+
+		headers := append([]any{"EVENT"}, data.Headers...)
+		switch {
+		case !reflect.DeepEqual(headers, w.headers):
+			w.headers = headers
+			segments = append(segments, &tableSegment{headers: tableCells(headers), newHeader: true})
+		case len(segments) == 0:
+			segments = append(segments, &tableSegment{headers: tableCells(headers)})
+		}
+		segment := segments[len(segments)-1]
+		for _, row := range data.Columns {
+			segment.rows = append(segment.rows, tableCells(append([]any{event.Type}, row...)))
+		}
+	}
+
+	for _, segment := range segments {
+		widths := w.widths
+		if segment.newHeader {
+			// fit all event types from the start, so they do not widen the first column later
+			widths, _ = widenColumns(nil, segment.headers)
+			for _, eventType := range []string{WatchEventAdded, WatchEventRemoved, WatchEventChanged} {
+				widths[0] = max(widths[0], len(eventType))
+			}
+		}
+		widened := false
+		for _, row := range segment.rows {
+			var rowWidened bool
+			widths, rowWidened = widenColumns(widths, row)
+			widened = widened || rowWidened
+		}
+		w.widths = widths
+
+		if segment.newHeader || widened {
+			if err := writeTableRow(out, widths, segment.headers); err != nil {
+				return err
+			}
+		}
+		for _, row := range segment.rows {
+			if err := writeTableRow(out, widths, row); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func tableCells(values []any) []string {
+	cells := make([]string, len(values))
+	for i, value := range values {
+		cells[i] = fmt.Sprint(value)
+	}
+	return cells
+}
+
+// widenColumns returns widths widened to fit cells and whether any column was widened.
+func widenColumns(widths []int, cells []string) ([]int, bool) {
+	res := make([]int, max(len(widths), len(cells)))
+	copy(res, widths)
+	widened := false
+	for i, cell := range cells {
+		if n := utf8.RuneCountInString(cell); n > res[i] {
+			res[i] = n
+			widened = true
+		}
+	}
+	return res, widened
+}
+
+// writeTableRow writes cells padded to widths, leaving at least two spaces between columns.
+func writeTableRow(w io.Writer, widths []int, cells []string) error {
+	var b strings.Builder
+	for i, cell := range cells {
+		if i == len(cells)-1 {
+			b.WriteString(cell)
+			break
+		}
+		fmt.Fprintf(&b, "%-*s", widths[i]+2, cell)
+	}
+	// rows may have more cells than the header, which are empty
+	_, err := io.WriteString(w, strings.TrimRight(b.String(), " ")+"\n")
+	return err
+}
@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+
+	. "github.com/ironcore-dev/dpservice-cli/cmd"
+	"github.com/ironcore-dev/dpservice-cli/fake"
+	"github.com/ironcore-dev/dpservice-go/api"
+	"github.com/ironcore-dev/dpservice-go/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+// steppedFactory hands out clients of a fake server whose ListInterfaces waits for a step,
+// so the test decides when a poll of watch mode sees the state of the server.
+type steppedFactory struct {
+	*fake.Server
+	steps chan struct{}
+}
+
+func (f steppedFactory) NewClient(ctx context.Context) (client.Client, func() error, error) {
+	c, cleanup, err := f.Server.NewClient(ctx)
+	return steppedClient{c, f.steps}, cleanup, err
+}
+
+type steppedClient struct {
+	client.Client
+	steps chan struct{}
+}
+
+func (c steppedClient) ListInterfaces(ctx context.Context, ignoredErrors ...[]uint32) (*api.InterfaceList, error) {
+	select {
+	case <-c.steps:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return c.Client.ListInterfaces(ctx, ignoredErrors...)
+}
+
+var _ = Describe("Watch", func() {
+	var (
+		ctx     = context.Background()
+		server  *fake.Server
+		c       client.Client
+		factory steppedFactory
+	)
+
+	createInterface := func(id string, ip string) {
+		ipv4 := netip.MustParseAddr(ip)
+		_, err := c.CreateInterface(ctx, &api.Interface{
+			InterfaceMeta: api.InterfaceMeta{ID: id},
+			Spec:          api.InterfaceSpec{VNI: 100, IPv4: &ipv4},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	deleteInterface := func(id string) {
+		_, err := c.DeleteInterface(ctx, id)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	// watch runs list interfaces in watch mode until the end of the test.
+	watch := func(args ...string) *gbytes.Buffer {
+		out := gbytes.NewBuffer()
+		cmd := List(factory)
+		cmd.SetArgs(append([]string{"interfaces", "--watch", "--interval=10ms"}, args...))
+		cmd.SetOut(out)
+		cmd.SetErr(out)
+
+		watchCtx, cancel := context.WithCancel(ctx)
+		done := make(chan error, 1)
+		go func() { done <- cmd.ExecuteContext(watchCtx) }()
+		DeferCleanup(func() {
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+		})
+		return out
+	}
+
+	BeforeEach(func() {
+		server = fake.NewServer()
+		DeferCleanup(server.Stop)
+
+		var (
+			cleanup func() error
+			err     error
+		)
+		c, cleanup, err = server.NewClient(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(cleanup)
+
+		factory = steppedFactory{Server: server, steps: make(chan struct{})}
+		createInterface("vm1", "10.0.0.1")
+	})
+
+	It("should print added, changed and removed objects", func() {
+		out := watch("-o", "name")
+
+		factory.steps <- struct{}{}
+		Eventually(out).Should(gbytes.Say(`interface/vm1 added\n`))
+
+		By("creating an interface")
+		createInterface("vm2", "10.0.0.2")
+		factory.steps <- struct{}{}
+		Eventually(out).Should(gbytes.Say(`interface/vm2 added\n`))
+
+		By("replacing an interface")
+		deleteInterface("vm1")
+		createInterface("vm1", "10.0.0.3")
+		factory.steps <- struct{}{}
+		Eventually(out).Should(gbytes.Say(`interface/vm1 changed\n`))
+
+		By("deleting an interface")
+		deleteInterface("vm2")
+		factory.steps <- struct{}{}
+		Eventually(out).Should(gbytes.Say(`interface/vm2 removed\n`))
+
+		By("polling without changes")
+		factory.steps <- struct{}{}
+		factory.steps <- struct{}{}
+		Consistently(out, "50ms").ShouldNot(gbytes.Say(`interface/`))
+	})
+
+	It("should align the rows of all polls with the header", func() {
+		out := watch("-o", "table")
+
+		factory.steps <- struct{}{}
+		Eventually(out).Should(gbytes.Say(`ADDED`))
+		createInterface("vm2", "10.0.0.2")
+		deleteInterface("vm1")
+		factory.steps <- struct{}{}
+		Eventually(out).Should(gbytes.Say(`REMOVED`))
+
+		lines := strings.Split(strings.TrimSpace(string(out.Contents())), "\n")
+		Expect(lines).To(HaveLen(4))
+		Expect(lines[0]).To(HavePrefix("EVENT    ID"))
+		column := strings.Index(lines[0], "ID")
+		for _, line := range lines[1:] {
+			Expect(strings.Index(line, "vm")).To(Equal(column), "line %q", line)
+			Expect(line).NotTo(HaveSuffix(" "))
+		}
+
+		By("widening a column")
+		createInterface("vm-with-a-long-id", "10.0.0.3")
+		factory.steps <- struct{}{}
+		Eventually(out).Should(gbytes.Say(`vm-with-a-long-id`))
+		lines = strings.Split(strings.TrimSpace(string(out.Contents())), "\n")
+		Expect(lines).To(HaveLen(6))
+		Expect(lines[4]).To(HavePrefix("EVENT"))
+		Expect(strings.Index(lines[4], "VNI")).To(Equal(strings.Index(lines[5], "100")))
+	})
+})
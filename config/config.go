@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+)
+
+// Config is the content of the dpservice-cli config file.
+type Config struct {
+	// CurrentContext is used if no context is selected explicitly.
+	CurrentContext string    `json:"currentContext,omitempty"`
+	Contexts       []Context `json:"contexts,omitempty"`
+	// Flags are defaults for persistent flags by flag name, e.g. "pretty: true".
+	// The selected context takes precedence over them.
+	Flags map[string]string `json:"flags,omitempty"`
+}
+
+// Context holds the connection settings for one dpservice instance.
+// Empty fields leave the corresponding flags at their defaults.
+type Context struct {
+	Name           string `json:"name"`
+	Address        string `json:"address,omitempty"`
+	ConnectTimeout string `json:"connectTimeout,omitempty"`
+	TLS            *TLS   `json:"tls,omitempty"`
+	Output         string `json:"output,omitempty"`
+}
+
+type TLS struct {
+	Enabled            bool   `json:"enabled,omitempty"`
+	CAFile             string `json:"caFile,omitempty"`
+	CertFile           string `json:"certFile,omitempty"`
+	KeyFile            string `json:"keyFile,omitempty"`
+	ServerName         string `json:"serverName,omitempty"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty"`
+}
+
+// DefaultPath returns the path of the config file in the user's config directory,
+// e.g. ~/.config/dpservice-cli/config.yaml.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "dpservice-cli", "config.yaml"), nil
+}
+
+// Load reads the config file at path. If the file does not exist and mustExist is false,
+// an empty config is returned.
+func Load(path string, mustExist bool) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !mustExist {
+			return &Config{}, nil
+		}
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Context returns the context with the given name. If name is empty, the current context
+// is returned, or nil if there is none.
+func (c *Config) Context(name string) (*Context, error) {
+	if name == "" {
+		name = c.CurrentContext
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	for i := range c.Contexts {
+		if c.Contexts[i].Name == name {
+			return &c.Contexts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("context %q not found in config", name)
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ironcore-dev/dpservice-cli/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Config", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		Expect(os.WriteFile(path, []byte(`
+currentContext: local
+flags:
+  connect-timeout: 2s
+contexts:
+- name: local
+  address: unix:///var/run/dpservice.sock
+- name: node1
+  address: node1:1337
+  connectTimeout: 10s
+  output: json
+  tls:
+    caFile: /etc/dpservice/ca.crt
+    serverName: dpservice.node1
+`), 0600)).To(Succeed())
+	})
+
+	It("should load contexts", func() {
+		cfg, err := config.Load(path, true)
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx, err := cfg.Context("node1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ctx).To(Equal(&config.Context{
+			Name:           "node1",
+			Address:        "node1:1337",
+			ConnectTimeout: "10s",
+			Output:         "json",
+			TLS:            &config.TLS{CAFile: "/etc/dpservice/ca.crt", ServerName: "dpservice.node1"},
+		}))
+	})
+
+	It("should load flags", func() {
+		cfg, err := config.Load(path, true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Flags).To(Equal(map[string]string{"connect-timeout": "2s"}))
+	})
+
+	It("should fall back to the current context", func() {
+		cfg, err := config.Load(path, true)
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx, err := cfg.Context("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ctx.Address).To(Equal("unix:///var/run/dpservice.sock"))
+	})
+
+	It("should fail for unknown contexts", func() {
+		cfg, err := config.Load(path, true)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = cfg.Context("node2")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should only require the file to exist if requested", func() {
+		missing := filepath.Join(filepath.Dir(path), "missing.yaml")
+
+		cfg, err := config.Load(missing, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Context("")).To(BeNil())
+
+		_, err = config.Load(missing, true)
+		Expect(err).To(HaveOccurred())
+	})
+})
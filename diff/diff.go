@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ironcore-dev/dpservice-go/api"
+)
+
+// Change is a single spec field whose desired value differs from the live one.
+type Change struct {
+	Path    string
+	Desired any
+	Live    any
+}
+
+// ignoredFields lists spec fields per kind that dpservice does not report back,
+// so they can never match the live state.
+var ignoredFields = map[string][]string{
+	api.InterfaceKind: {"spec.pxe"},
+}
+
+// Spec compares the spec of desired against the spec of live.
+// Only fields set in desired are compared, fields populated by dpservice
+// (e.g. underlay routes) are not considered drift. As fields set to their zero
+// value cannot be told apart from unset ones, they are not compared either;
+// use SourceSpec to compare them.
+// A nil live object reports every field set in desired as a change.
+func Spec(desired, live any) ([]Change, error) {
+	desiredFields, liveFields, err := objectSpecFields(desired, live)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, path := range sortedKeys(desiredFields) {
+		if !isZero(desiredFields[path]) {
+			paths = append(paths, path)
+		}
+	}
+	return compare(desired, paths, desiredFields, liveFields), nil
+}
+
+// SourceSpec is like Spec, but also compares the fields set to their zero value in source,
+// the spec desired was decoded from.
+func SourceSpec(desired, live, source any) ([]Change, error) {
+	desiredFields, liveFields, err := objectSpecFields(desired, live)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceFields := make(map[string]any)
+	flatten("spec", source, sourceFields)
+
+	compared := make(map[string]any)
+	for path, value := range desiredFields {
+		if _, ok := sourceFields[path]; ok || !isZero(value) {
+			compared[path] = value
+		}
+	}
+	for path, value := range sourceFields {
+		// desired omits fields set to their zero value if they are tagged with omitempty
+		if _, ok := desiredFields[path]; !ok && isZero(value) && !isZero(liveFields[path]) {
+			compared[path] = value
+		}
+	}
+	return compare(desired, sortedKeys(compared), compared, liveFields), nil
+}
+
+func objectSpecFields(desired, live any) (desiredFields, liveFields map[string]any, err error) {
+	desiredFields, err = specFields(desired)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading desired spec: %w", err)
+	}
+	liveFields = make(map[string]any)
+	if live != nil {
+		liveFields, err = specFields(live)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading live spec: %w", err)
+		}
+	}
+	return desiredFields, liveFields, nil
+}
+
+// compare returns the changes of the fields at paths, except for the fields ignored for the kind of desired.
+func compare(desired any, paths []string, desiredFields, liveFields map[string]any) []Change {
+	ignored := ignoredFields[reflect.Indirect(reflect.ValueOf(desired)).Type().Name()]
+
+	var changes []Change
+	for _, path := range paths {
+		if isIgnored(path, ignored) {
+			continue
+		}
+
+		desiredValue, liveValue := desiredFields[path], liveFields[path]
+		if !equal(desiredValue, liveValue) {
+			changes = append(changes, Change{
+				Path:    path,
+				Desired: desiredValue,
+				Live:    liveValue,
+			})
+		}
+	}
+	return changes
+}
+
+// Unified writes changes of the object called name in unified diff format,
+// the live state being the old and the desired state being the new side.
+func Unified(w io.Writer, name string, changes []Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- live/%s\n", name)
+	fmt.Fprintf(&sb, "+++ desired/%s\n", name)
+	for _, change := range changes {
+		fmt.Fprintf(&sb, "@@ %s @@\n", change.Path)
+		if change.Live != nil {
+			fmt.Fprintf(&sb, "-%s: %s\n", change.Path, formatValue(change.Live))
+		}
+		fmt.Fprintf(&sb, "+%s: %s\n", change.Path, formatValue(change.Desired))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func formatValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func specFields(obj any) (map[string]any, error) {
+	v := reflect.Indirect(reflect.ValueOf(obj))
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("object %T must be a struct", obj)
+	}
+	spec := v.FieldByName("Spec")
+	if !spec.IsValid() {
+		return nil, fmt.Errorf("object %T has no spec", obj)
+	}
+
+	data, err := json.Marshal(spec.Interface())
+	if err != nil {
+		return nil, err
+	}
+	var m any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]any)
+	flatten("spec", m, fields)
+	return fields, nil
+}
+
+// flatten stores every leaf of v in fields under its dotted path.
+// Lists are kept as a single leaf, as dpservice replaces them as a whole.
+func flatten(path string, v any, fields map[string]any) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		fields[path] = v
+		return
+	}
+	for key, value := range m {
+		flatten(path+"."+key, value, fields)
+	}
+}
+
+func isZero(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case float64:
+		return v == 0
+	case bool:
+		return !v
+	case []any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+func isIgnored(path string, ignored []string) bool {
+	for _, prefix := range ignored {
+		if path == prefix || strings.HasPrefix(path, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// equal compares strings case-insensitively, as dpservice normalizes
+// values like firewall actions and directions.
+func equal(desired, live any) bool {
+	if desiredStr, ok := desired.(string); ok {
+		liveStr, ok := live.(string)
+		return ok && strings.EqualFold(desiredStr, liveStr)
+	}
+	return reflect.DeepEqual(desired, live)
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"bytes"
+	"net/netip"
+
+	. "github.com/ironcore-dev/dpservice-cli/diff"
+	"github.com/ironcore-dev/dpservice-go/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diff", func() {
+	Context("Spec", func() {
+		var (
+			desired *api.Interface
+			live    *api.Interface
+		)
+		BeforeEach(func() {
+			ipv4 := netip.MustParseAddr("10.200.1.4")
+			underlayRoute := netip.MustParseAddr("fc00::1")
+			desired = &api.Interface{
+				InterfaceMeta: api.InterfaceMeta{ID: "vm1"},
+				Spec: api.InterfaceSpec{
+					VNI:  200,
+					IPv4: &ipv4,
+					PXE:  &api.PXE{Server: "10.0.0.1", FileName: "boot.ipxe"},
+				},
+			}
+			live = &api.Interface{
+				InterfaceMeta: api.InterfaceMeta{ID: "vm1"},
+				Spec: api.InterfaceSpec{
+					VNI:           200,
+					IPv4:          &ipv4,
+					UnderlayRoute: &underlayRoute,
+				},
+			}
+		})
+
+		It("should ignore fields only set by dpservice", func() {
+			changes, err := Spec(desired, live)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(BeEmpty())
+		})
+
+		It("should report changed fields", func() {
+			desired.Spec.VNI = 300
+
+			changes, err := Spec(desired, live)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(ConsistOf(Change{Path: "spec.vni", Desired: float64(300), Live: float64(200)}))
+		})
+
+		It("should not report fields set to their zero value without their source", func() {
+			desired.Spec.VNI = 0
+
+			changes, err := Spec(desired, live)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(BeEmpty())
+		})
+
+		It("should report fields set to their zero value in the source", func() {
+			desired.Spec.VNI = 0
+			live.Spec.Device = "net_tap2"
+
+			changes, err := SourceSpec(desired, live, map[string]any{"vni": float64(0), "device": ""})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(ConsistOf(
+				Change{Path: "spec.device", Desired: "", Live: "net_tap2"},
+				Change{Path: "spec.vni", Desired: float64(0), Live: float64(200)},
+			))
+
+			By("leaving out fields missing from the source")
+			changes, err = SourceSpec(desired, live, map[string]any{"primary_ipv4": "10.200.1.4"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(BeEmpty())
+		})
+
+		It("should report all desired fields of a missing object", func() {
+			changes, err := Spec(desired, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(HaveLen(2))
+		})
+	})
+
+	Context("Unified", func() {
+		It("should render changes as unified diff", func() {
+			var buf bytes.Buffer
+			Expect(Unified(&buf, "Interface/vm1", []Change{
+				{Path: "spec.vni", Desired: float64(300), Live: float64(200)},
+			})).To(Succeed())
+			Expect(buf.String()).To(Equal("--- live/Interface/vm1\n+++ desired/Interface/vm1\n@@ spec.vni @@\n-spec.vni: 200\n+spec.vni: 300\n"))
+		})
+	})
+})
@@ -5,11 +5,13 @@ package dynamic
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/netip"
 
 	"github.com/ironcore-dev/dpservice-go/api"
 	structured "github.com/ironcore-dev/dpservice-go/client"
+	apierrors "github.com/ironcore-dev/dpservice-go/errors"
 )
 
 type ObjectKey interface {
@@ -62,10 +64,13 @@ type RouteKey struct {
 }
 
 func (k RouteKey) String() string {
-	return fmt.Sprintf("%d:%s-%d:%s", k.VNI, k.Prefix, k.NextHopVNI, k.NextHopIP)
+	return fmt.Sprintf("%d:%s", k.VNI, k.Name())
 }
 
 func (k RouteKey) Name() string {
+	if !k.NextHopIP.IsValid() {
+		return k.Prefix.String()
+	}
 	return fmt.Sprintf("%s-%d:%s", k.Prefix, k.NextHopVNI, k.NextHopIP)
 }
 
@@ -140,13 +145,39 @@ type FirewallRuleKey struct {
 }
 
 func (k FirewallRuleKey) String() string {
-	return fmt.Sprintf("%s-%s", k.InterfaceID, k.RuleID)
+	return fmt.Sprintf("%s/%s", k.InterfaceID, k.RuleID)
 }
 
 func (k FirewallRuleKey) Name() string {
 	return k.String()
 }
 
+// VNIKey identifies a VNI. It is the parent key of routes.
+type VNIKey struct {
+	VNI uint32
+}
+
+func (k VNIKey) String() string {
+	return fmt.Sprintf("%d", k.VNI)
+}
+
+func (k VNIKey) Name() string {
+	return k.String()
+}
+
+// NatIPKey identifies a NAT IP. It is the parent key of neighbor NATs.
+type NatIPKey struct {
+	NatIP netip.Addr
+}
+
+func (k NatIPKey) String() string {
+	return k.NatIP.String()
+}
+
+func (k NatIPKey) Name() string {
+	return k.String()
+}
+
 type emptyKey struct{}
 
 func (emptyKey) String() string {
@@ -160,60 +191,112 @@ func (emptyKey) Name() string {
 var EmptyKey ObjectKey = emptyKey{}
 
 // returns object key (parameters needed for deletion)
-func ObjectKeyFromObject(obj any) ObjectKey {
+// If obj lacks a field of its key, the key is returned with the zero value for it together with an error.
+func ObjectKeyFromObject(obj any) (ObjectKey, error) {
 	switch obj := obj.(type) {
 	case *api.Interface:
-		return InterfaceKey{ID: obj.ID}
+		return InterfaceKey{ID: obj.ID}, nil
 	case *api.Prefix:
 		return PrefixKey{
 			InterfaceID: obj.InterfaceID,
 			Prefix:      obj.Spec.Prefix,
-		}
+		}, nil
 	case *api.Route:
-		return RouteKey{
-			VNI:    obj.VNI,
-			Prefix: *obj.Spec.Prefix,
+		key := RouteKey{VNI: obj.VNI}
+		if nextHop := obj.Spec.NextHop; nextHop != nil {
+			key.NextHopVNI = nextHop.VNI
+			if nextHop.IP != nil {
+				key.NextHopIP = *nextHop.IP
+			}
 		}
+		if obj.Spec.Prefix == nil {
+			return key, fmt.Errorf("route of vni %d has no prefix", obj.VNI)
+		}
+		key.Prefix = *obj.Spec.Prefix
+		return key, nil
 	case *api.VirtualIP:
 		return VirtualIPKey{
 			InterfaceID: obj.InterfaceID,
-		}
+		}, nil
 	case *api.LoadBalancer:
 		return LoadBalancerKey{
 			ID: obj.ID,
-		}
+		}, nil
 	case *api.LoadBalancerPrefix:
 		return LoadBalancerPrefixKey{
 			Prefix:      obj.Spec.Prefix,
 			InterfaceID: obj.InterfaceID,
-		}
+		}, nil
 	case *api.LoadBalancerTarget:
-		return LoadBalancerTargetKey{
-			TargetIP:       *obj.Spec.TargetIP,
+		key := LoadBalancerTargetKey{
 			LoadBalancerID: obj.LoadbalancerID,
 		}
+		if obj.Spec.TargetIP == nil {
+			return key, fmt.Errorf("loadbalancer target of %s has no target_ip", obj.LoadbalancerID)
+		}
+		key.TargetIP = *obj.Spec.TargetIP
+		return key, nil
 	case *api.Nat:
 		return NatKey{
 			InterfaceID: obj.InterfaceID,
-		}
+		}, nil
 	case *api.NeighborNat:
-		return NeighborNatKey{
-			NatIP:   *obj.NatIP,
+		key := NeighborNatKey{
 			Vni:     obj.Spec.Vni,
 			MinPort: obj.Spec.MinPort,
 			MaxPort: obj.Spec.MaxPort,
 		}
+		if obj.NatIP == nil {
+			return key, fmt.Errorf("neighbor nat of vni %d has no nat_ip", obj.Spec.Vni)
+		}
+		key.NatIP = *obj.NatIP
+		return key, nil
 	case *api.FirewallRule:
 		return FirewallRuleKey{
 			RuleID:      obj.Spec.RuleID,
 			InterfaceID: obj.InterfaceID,
-		}
+		}, nil
 	default:
-		return EmptyKey
+		return EmptyKey, nil
+	}
+}
+
+// NotFoundError is returned by Get if dpservice does not know the object identified by Key.
+type NotFoundError struct {
+	Key ObjectKey
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("object %T %s not found", e.Key, e.Key)
+}
+
+// notFoundCodes are the status codes dpservice uses to report a missing object.
+var notFoundCodes = []uint32{
+	apierrors.NOT_FOUND,
+	apierrors.NO_VM,
+	apierrors.NO_LB,
+	apierrors.NO_BACKIP,
+	apierrors.ROUTE_NOT_FOUND,
+	apierrors.SNAT_NO_DATA,
+	apierrors.DNAT_NO_DATA,
+}
+
+func IsNotFound(err error) bool {
+	var notFound *NotFoundError
+	return errors.As(err, &notFound)
+}
+
+// notFound converts a dpservice "not found" status error into a NotFoundError for key.
+func notFound(key ObjectKey, err error) error {
+	if apierrors.IsStatusErrorCode(err, notFoundCodes...) {
+		return &NotFoundError{Key: key}
 	}
+	return err
 }
 
 type Client interface {
+	Get(ctx context.Context, key ObjectKey) (any, error)
+	List(ctx context.Context, kind string, parentKey ObjectKey) ([]any, error)
 	Create(ctx context.Context, obj any) (any, error)
 	Delete(ctx context.Context, obj any) (any, error)
 }
@@ -222,6 +305,86 @@ type client struct {
 	structured structured.Client
 }
 
+// Get returns the live state of the object identified by key.
+// Kinds without a dedicated get call in dpservice are looked up in the list of their parent.
+func (c *client) Get(ctx context.Context, key ObjectKey) (any, error) {
+	switch key := key.(type) {
+	case InterfaceKey:
+		res, err := c.structured.GetInterface(ctx, key.ID)
+		if err != nil {
+			return res, notFound(key, err)
+		}
+		return res, nil
+	case PrefixKey:
+		return c.find(ctx, key, api.PrefixKind, InterfaceKey{ID: key.InterfaceID}, func(obj any) bool {
+			return obj.(*api.Prefix).Spec.Prefix == key.Prefix
+		})
+	case RouteKey:
+		// routes to the same prefix with different next hops are different objects
+		return c.find(ctx, key, api.RouteKind, VNIKey{VNI: key.VNI}, func(obj any) bool {
+			routeKey, err := ObjectKeyFromObject(obj)
+			return err == nil && routeKey == key
+		})
+	case VirtualIPKey:
+		res, err := c.structured.GetVirtualIP(ctx, key.InterfaceID)
+		if err != nil {
+			return res, notFound(key, err)
+		}
+		return res, nil
+	case LoadBalancerKey:
+		res, err := c.structured.GetLoadBalancer(ctx, key.ID)
+		if err != nil {
+			return res, notFound(key, err)
+		}
+		return res, nil
+	case LoadBalancerPrefixKey:
+		return c.find(ctx, key, api.LoadBalancerPrefixKind, InterfaceKey{ID: key.InterfaceID}, func(obj any) bool {
+			return obj.(*api.LoadBalancerPrefix).Spec.Prefix == key.Prefix
+		})
+	case LoadBalancerTargetKey:
+		return c.find(ctx, key, api.LoadBalancerTargetKind, LoadBalancerKey{ID: key.LoadBalancerID}, func(obj any) bool {
+			target := obj.(*api.LoadBalancerTarget)
+			return target.Spec.TargetIP != nil && *target.Spec.TargetIP == key.TargetIP
+		})
+	case NatKey:
+		res, err := c.structured.GetNat(ctx, key.InterfaceID)
+		if err != nil {
+			return res, notFound(key, err)
+		}
+		return res, nil
+	case NeighborNatKey:
+		return c.find(ctx, key, api.NeighborNatKind, NatIPKey{NatIP: key.NatIP}, func(obj any) bool {
+			nat := obj.(*api.NeighborNat)
+			return nat.Spec.Vni == key.Vni && nat.Spec.MinPort == key.MinPort && nat.Spec.MaxPort == key.MaxPort
+		})
+	case FirewallRuleKey:
+		res, err := c.structured.GetFirewallRule(ctx, key.InterfaceID, key.RuleID)
+		if err != nil {
+			return res, notFound(key, err)
+		}
+		return res, nil
+	default:
+		return nil, fmt.Errorf("unsupported object key %T", key)
+	}
+}
+
+// find returns the first object of kind below parentKey that matches. A missing parent is reported as key not found.
+func (c *client) find(ctx context.Context, key ObjectKey, kind string, parentKey ObjectKey, match func(obj any) bool) (any, error) {
+	objs, err := c.List(ctx, kind, parentKey)
+	if err != nil {
+		if IsNotFound(err) {
+			return nil, &NotFoundError{Key: key}
+		}
+		return nil, notFound(key, err)
+	}
+	for _, obj := range objs {
+		if match(obj) {
+			return obj, nil
+		}
+	}
+	return nil, &NotFoundError{Key: key}
+}
+
 func (c *client) Create(ctx context.Context, obj any) (any, error) {
 	switch obj := obj.(type) {
 	case *api.Interface:
@@ -300,6 +463,10 @@ func (c *client) Create(ctx context.Context, obj any) (any, error) {
 }
 
 func (c *client) Delete(ctx context.Context, obj any) (any, error) {
+	// the structured client dereferences the fields of the key
+	if _, err := ObjectKeyFromObject(obj); err != nil {
+		return nil, err
+	}
 	switch obj := obj.(type) {
 	case *api.Interface:
 		return c.structured.DeleteInterface(ctx, obj.ID)
@@ -0,0 +1,16 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package dynamic_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestDynamic(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Dynamic Suite")
+}
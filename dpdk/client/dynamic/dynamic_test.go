@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package dynamic_test
+
+import (
+	"context"
+	"net/netip"
+
+	. "github.com/ironcore-dev/dpservice-cli/dpdk/client/dynamic"
+	"github.com/ironcore-dev/dpservice-cli/fake"
+	"github.com/ironcore-dev/dpservice-go/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		ctx = context.Background()
+		dc  Client
+	)
+
+	BeforeEach(func() {
+		server := fake.NewServer()
+		DeferCleanup(server.Stop)
+
+		c, cleanup, err := server.NewClient(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(cleanup)
+		dc = NewFromStructured(c)
+	})
+
+	It("should get routes by their full key, including the next hop", func() {
+		ip := netip.MustParseAddr("10.0.0.1")
+		_, err := dc.Create(ctx, &api.Interface{
+			InterfaceMeta: api.InterfaceMeta{ID: "vm1"},
+			Spec:          api.InterfaceSpec{VNI: 100, IPv4: &ip},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		prefix := netip.MustParsePrefix("10.0.2.0/24")
+		nextHop := netip.MustParseAddr("fc00::2")
+		_, err = dc.Create(ctx, &api.Route{
+			RouteMeta: api.RouteMeta{VNI: 100},
+			Spec:      api.RouteSpec{Prefix: &prefix, NextHop: &api.RouteNextHop{VNI: 200, IP: &nextHop}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		key := RouteKey{VNI: 100, Prefix: prefix, NextHopVNI: 200, NextHopIP: nextHop}
+		_, err = dc.Get(ctx, key)
+		Expect(err).NotTo(HaveOccurred())
+
+		key.NextHopIP = netip.MustParseAddr("fc00::3")
+		_, err = dc.Get(ctx, key)
+		Expect(IsNotFound(err)).To(BeTrue())
+
+		key.NextHopIP, key.NextHopVNI = nextHop, 300
+		_, err = dc.Get(ctx, key)
+		Expect(IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should not delete objects lacking a field of their key", func() {
+		_, err := dc.Delete(ctx, &api.Route{RouteMeta: api.RouteMeta{VNI: 100}})
+		Expect(err).To(MatchError("route of vni 100 has no prefix"))
+
+		_, err = dc.Delete(ctx, &api.LoadBalancerTarget{LoadBalancerTargetMeta: api.LoadBalancerTargetMeta{LoadbalancerID: "lb1"}})
+		Expect(err).To(MatchError("loadbalancer target of lb1 has no target_ip"))
+	})
+})
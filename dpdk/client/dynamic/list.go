@@ -0,0 +1,263 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package dynamic
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+
+	"github.com/ironcore-dev/dpservice-go/api"
+)
+
+// List returns the live objects of kind below parentKey.
+//
+// The parent key depends on the kind:
+//   - Prefix, LoadBalancerPrefix, FirewallRule, VirtualIP and Nat: InterfaceKey
+//   - Route: VNIKey
+//   - NeighborNat: NatIPKey
+//   - LoadBalancerTarget: LoadBalancerKey
+//   - LoadBalancer: LoadBalancerKey, returning the load balancer if it exists
+//
+// If parentKey is nil or EmptyKey, the objects below all interfaces, below the VNIs of all
+// interfaces and below the NAT IPs of all NATs are returned. Load balancers cannot be listed
+// by dpservice, so load balancers and their targets require a LoadBalancerKey.
+func (c *client) List(ctx context.Context, kind string, parentKey ObjectKey) ([]any, error) {
+	if parentKey == nil {
+		parentKey = EmptyKey
+	}
+
+	switch kind {
+	case api.InterfaceKind:
+		if parentKey != EmptyKey {
+			return nil, unsupportedParent(kind, parentKey)
+		}
+		list, err := c.structured.ListInterfaces(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return listItems(list.Items), nil
+	case api.PrefixKind, api.LoadBalancerPrefixKind, api.FirewallRuleKind, api.VirtualIPKind, api.NatKind:
+		ids, err := c.interfaceIDs(ctx, kind, parentKey)
+		if err != nil {
+			return nil, err
+		}
+		var res []any
+		for _, id := range ids {
+			objs, err := c.listInterfaceChildren(ctx, kind, id)
+			if err != nil {
+				return nil, notFound(InterfaceKey{ID: id}, err)
+			}
+			res = append(res, objs...)
+		}
+		return res, nil
+	case api.RouteKind:
+		vnis, err := c.vnis(ctx, parentKey)
+		if err != nil {
+			return nil, err
+		}
+		var res []any
+		for _, vni := range vnis {
+			list, err := c.structured.ListRoutes(ctx, vni)
+			if err != nil {
+				return nil, fmt.Errorf("error listing routes of vni %d: %w", vni, err)
+			}
+			res = append(res, listItems(list.Items)...)
+		}
+		return res, nil
+	case api.LoadBalancerKind:
+		key, ok := parentKey.(LoadBalancerKey)
+		if !ok {
+			return nil, unsupportedParent(kind, parentKey)
+		}
+		res, err := c.structured.GetLoadBalancer(ctx, key.ID)
+		if err != nil {
+			if IsNotFound(notFound(key, err)) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return []any{res}, nil
+	case api.LoadBalancerTargetKind:
+		key, ok := parentKey.(LoadBalancerKey)
+		if !ok {
+			return nil, unsupportedParent(kind, parentKey)
+		}
+		list, err := c.structured.ListLoadBalancerTargets(ctx, key.ID)
+		if err != nil {
+			return nil, notFound(key, err)
+		}
+		return listItems(list.Items), nil
+	case api.NeighborNatKind:
+		natIPs, err := c.natIPs(ctx, parentKey)
+		if err != nil {
+			return nil, err
+		}
+		var res []any
+		for _, natIP := range natIPs {
+			natIP := natIP
+			list, err := c.structured.ListNeighborNats(ctx, &natIP)
+			if err != nil {
+				return nil, fmt.Errorf("error listing neighbor nats of %s: %w", natIP, err)
+			}
+			for _, nat := range list.Items {
+				res = append(res, &api.NeighborNat{
+					TypeMeta:        api.TypeMeta{Kind: api.NeighborNatKind},
+					NeighborNatMeta: api.NeighborNatMeta{NatIP: &natIP},
+					Spec: api.NeighborNatSpec{
+						Vni:           nat.Spec.Vni,
+						MinPort:       nat.Spec.MinPort,
+						MaxPort:       nat.Spec.MaxPort,
+						UnderlayRoute: nat.Spec.UnderlayRoute,
+					},
+					Status: nat.Status,
+				})
+			}
+		}
+		return res, nil
+	default:
+		return nil, fmt.Errorf("kind %s cannot be listed", kind)
+	}
+}
+
+func (c *client) listInterfaceChildren(ctx context.Context, kind, interfaceID string) ([]any, error) {
+	switch kind {
+	case api.PrefixKind:
+		list, err := c.structured.ListPrefixes(ctx, interfaceID)
+		if err != nil {
+			return nil, err
+		}
+		return listItems(list.Items), nil
+	case api.LoadBalancerPrefixKind:
+		list, err := c.structured.ListLoadBalancerPrefixes(ctx, interfaceID)
+		if err != nil {
+			return nil, err
+		}
+		res := make([]any, len(list.Items))
+		for i, prefix := range list.Items {
+			res[i] = &api.LoadBalancerPrefix{
+				TypeMeta:               api.TypeMeta{Kind: api.LoadBalancerPrefixKind},
+				LoadBalancerPrefixMeta: api.LoadBalancerPrefixMeta{InterfaceID: prefix.InterfaceID},
+				Spec: api.LoadBalancerPrefixSpec{
+					Prefix:        prefix.Spec.Prefix,
+					UnderlayRoute: prefix.Spec.UnderlayRoute,
+				},
+				Status: prefix.Status,
+			}
+		}
+		return res, nil
+	case api.FirewallRuleKind:
+		list, err := c.structured.ListFirewallRules(ctx, interfaceID)
+		if err != nil {
+			return nil, err
+		}
+		return listItems(list.Items), nil
+	case api.VirtualIPKind:
+		return c.getOptional(ctx, VirtualIPKey{InterfaceID: interfaceID})
+	case api.NatKind:
+		return c.getOptional(ctx, NatKey{InterfaceID: interfaceID})
+	default:
+		return nil, fmt.Errorf("kind %s is not below interfaces", kind)
+	}
+}
+
+// getOptional returns the object identified by key, or nothing if it does not exist.
+func (c *client) getOptional(ctx context.Context, key ObjectKey) ([]any, error) {
+	obj, err := c.Get(ctx, key)
+	if err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return []any{obj}, nil
+}
+
+func (c *client) interfaceIDs(ctx context.Context, kind string, parentKey ObjectKey) ([]string, error) {
+	switch key := parentKey.(type) {
+	case InterfaceKey:
+		return []string{key.ID}, nil
+	case emptyKey:
+		list, err := c.structured.ListInterfaces(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error listing interfaces: %w", err)
+		}
+		ids := make([]string, len(list.Items))
+		for i, iface := range list.Items {
+			ids[i] = iface.ID
+		}
+		return ids, nil
+	default:
+		return nil, unsupportedParent(kind, parentKey)
+	}
+}
+
+// vnis returns the VNI of parentKey, or the VNIs of all interfaces.
+func (c *client) vnis(ctx context.Context, parentKey ObjectKey) ([]uint32, error) {
+	switch key := parentKey.(type) {
+	case VNIKey:
+		return []uint32{key.VNI}, nil
+	case emptyKey:
+		list, err := c.structured.ListInterfaces(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error listing interfaces: %w", err)
+		}
+		seen := make(map[uint32]struct{})
+		var vnis []uint32
+		for _, iface := range list.Items {
+			if _, ok := seen[iface.Spec.VNI]; !ok {
+				seen[iface.Spec.VNI] = struct{}{}
+				vnis = append(vnis, iface.Spec.VNI)
+			}
+		}
+		sort.Slice(vnis, func(i, j int) bool { return vnis[i] < vnis[j] })
+		return vnis, nil
+	default:
+		return nil, unsupportedParent(api.RouteKind, parentKey)
+	}
+}
+
+// natIPs returns the NAT IP of parentKey, or the NAT IPs of all NATs.
+func (c *client) natIPs(ctx context.Context, parentKey ObjectKey) ([]netip.Addr, error) {
+	switch key := parentKey.(type) {
+	case NatIPKey:
+		return []netip.Addr{key.NatIP}, nil
+	case emptyKey:
+		nats, err := c.List(ctx, api.NatKind, EmptyKey)
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[netip.Addr]struct{})
+		var natIPs []netip.Addr
+		for _, obj := range nats {
+			natIP := obj.(*api.Nat).Spec.NatIP
+			if natIP == nil || !natIP.IsValid() {
+				continue
+			}
+			if _, ok := seen[*natIP]; !ok {
+				seen[*natIP] = struct{}{}
+				natIPs = append(natIPs, *natIP)
+			}
+		}
+		return natIPs, nil
+	default:
+		return nil, unsupportedParent(api.NeighborNatKind, parentKey)
+	}
+}
+
+func unsupportedParent(kind string, parentKey ObjectKey) error {
+	if parentKey == EmptyKey {
+		return fmt.Errorf("%s cannot be listed without a parent key", kind)
+	}
+	return fmt.Errorf("unsupported parent key %T for %s", parentKey, kind)
+}
+
+func listItems[T any](items []T) []any {
+	res := make([]any, len(items))
+	for i := range items {
+		res[i] = &items[i]
+	}
+	return res
+}
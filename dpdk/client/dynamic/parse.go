@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package dynamic
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/ironcore-dev/dpservice-go/api"
+)
+
+// ParseObjectKey parses the key of an object of kind from its String() form, e.g.
+// "100:10.0.0.0/24-0:fc00::1" for a route or "vm1/r12" for a firewall rule.
+func ParseObjectKey(kind, s string) (ObjectKey, error) {
+	if s == "" {
+		return nil, fmt.Errorf("empty %s key", kind)
+	}
+
+	key, err := parseObjectKey(kind, s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s key %q: %w", kind, s, err)
+	}
+	return key, nil
+}
+
+func parseObjectKey(kind, s string) (ObjectKey, error) {
+	switch kind {
+	case api.InterfaceKind:
+		return InterfaceKey{ID: s}, nil
+	case api.VirtualIPKind:
+		return VirtualIPKey{InterfaceID: s}, nil
+	case api.NatKind:
+		return NatKey{InterfaceID: s}, nil
+	case api.LoadBalancerKind:
+		return LoadBalancerKey{ID: s}, nil
+	case api.PrefixKind:
+		interfaceID, prefix, err := splitKey(s, "/", "<interface-id>/<prefix>")
+		if err != nil {
+			return nil, err
+		}
+		p, err := netip.ParsePrefix(prefix)
+		if err != nil {
+			return nil, err
+		}
+		return PrefixKey{InterfaceID: interfaceID, Prefix: p}, nil
+	case api.FirewallRuleKind:
+		interfaceID, ruleID, err := splitKey(s, "/", "<interface-id>/<rule-id>")
+		if err != nil {
+			return nil, err
+		}
+		return FirewallRuleKey{InterfaceID: interfaceID, RuleID: ruleID}, nil
+	case api.LoadBalancerPrefixKind:
+		// prefixes never contain '-', interface IDs may
+		i := strings.LastIndex(s, "-")
+		if i <= 0 {
+			return nil, fmt.Errorf("expected <interface-id>-<prefix>")
+		}
+		p, err := netip.ParsePrefix(s[i+1:])
+		if err != nil {
+			return nil, err
+		}
+		return LoadBalancerPrefixKey{InterfaceID: s[:i], Prefix: p}, nil
+	case api.LoadBalancerTargetKind:
+		// IPs never contain '-', load balancer IDs may
+		i := strings.LastIndex(s, "-")
+		if i <= 0 {
+			return nil, fmt.Errorf("expected <loadbalancer-id>-<target-ip>")
+		}
+		ip, err := netip.ParseAddr(s[i+1:])
+		if err != nil {
+			return nil, err
+		}
+		return LoadBalancerTargetKey{LoadBalancerID: s[:i], TargetIP: ip}, nil
+	case api.RouteKind:
+		return parseRouteKey(s)
+	case api.NeighborNatKind:
+		return parseNeighborNatKey(s)
+	default:
+		return nil, fmt.Errorf("unsupported kind %s", kind)
+	}
+}
+
+// parseRouteKey parses <vni>:<prefix>[-<next-hop-vni>:<next-hop-ip>].
+func parseRouteKey(s string) (ObjectKey, error) {
+	vni, rest, err := splitKey(s, ":", "<vni>:<prefix>[-<next-hop-vni>:<next-hop-ip>]")
+	if err != nil {
+		return nil, err
+	}
+	var key RouteKey
+	if key.VNI, err = parseUint32(vni); err != nil {
+		return nil, err
+	}
+
+	prefix, nextHop, hasNextHop := strings.Cut(rest, "-")
+	if key.Prefix, err = netip.ParsePrefix(prefix); err != nil {
+		return nil, err
+	}
+	if !hasNextHop {
+		return key, nil
+	}
+
+	nextHopVNI, nextHopIP, err := splitKey(nextHop, ":", "<next-hop-vni>:<next-hop-ip>")
+	if err != nil {
+		return nil, err
+	}
+	if key.NextHopVNI, err = parseUint32(nextHopVNI); err != nil {
+		return nil, err
+	}
+	if key.NextHopIP, err = netip.ParseAddr(nextHopIP); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// parseNeighborNatKey parses <vni>-<nat-ip>:<<min-port>,<max-port>>.
+func parseNeighborNatKey(s string) (ObjectKey, error) {
+	const format = "<vni>-<nat-ip>:<<min-port>,<max-port>>"
+	rest, ports, ok := strings.Cut(s, ":<")
+	if !ok || !strings.HasSuffix(ports, ">") {
+		return nil, fmt.Errorf("expected %s", format)
+	}
+	vni, natIP, err := splitKey(rest, "-", format)
+	if err != nil {
+		return nil, err
+	}
+	minPort, maxPort, err := splitKey(strings.TrimSuffix(ports, ">"), ",", format)
+	if err != nil {
+		return nil, err
+	}
+
+	var key NeighborNatKey
+	if key.Vni, err = parseUint32(vni); err != nil {
+		return nil, err
+	}
+	if key.NatIP, err = netip.ParseAddr(natIP); err != nil {
+		return nil, err
+	}
+	if key.MinPort, err = parseUint32(minPort); err != nil {
+		return nil, err
+	}
+	if key.MaxPort, err = parseUint32(maxPort); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// splitKey splits s at the first sep into two non-empty parts.
+func splitKey(s, sep, format string) (string, string, error) {
+	before, after, ok := strings.Cut(s, sep)
+	if !ok || before == "" || after == "" {
+		return "", "", fmt.Errorf("expected %s", format)
+	}
+	return before, after, nil
+}
+
+func parseUint32(s string) (uint32, error) {
+	v, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(v), nil
+}
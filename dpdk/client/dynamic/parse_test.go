@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package dynamic_test
+
+import (
+	"net/netip"
+
+	. "github.com/ironcore-dev/dpservice-cli/dpdk/client/dynamic"
+	"github.com/ironcore-dev/dpservice-go/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ObjectKey", func() {
+	Context("ObjectKeyFromObject", func() {
+		It("should include the next hop of routes", func() {
+			prefix := netip.MustParsePrefix("10.0.0.0/24")
+			nextHopIP := netip.MustParseAddr("fc00::1")
+			route := &api.Route{
+				RouteMeta: api.RouteMeta{VNI: 100},
+				Spec: api.RouteSpec{
+					Prefix:  &prefix,
+					NextHop: &api.RouteNextHop{VNI: 200, IP: &nextHopIP},
+				},
+			}
+
+			key, err := ObjectKeyFromObject(route)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key).To(Equal(RouteKey{VNI: 100, Prefix: prefix, NextHopVNI: 200, NextHopIP: nextHopIP}))
+			Expect(key.String()).To(Equal("100:10.0.0.0/24-200:fc00::1"))
+		})
+
+		It("should separate the interface and rule of firewall rules with a slash", func() {
+			key, err := ObjectKeyFromObject(&api.FirewallRule{
+				FirewallRuleMeta: api.FirewallRuleMeta{InterfaceID: "vm1"},
+				Spec:             api.FirewallRuleSpec{RuleID: "r12"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key.String()).To(Equal("vm1/r12"))
+		})
+
+		It("should fail for objects lacking a field of their key", func() {
+			_, err := ObjectKeyFromObject(&api.LoadBalancerTarget{
+				LoadBalancerTargetMeta: api.LoadBalancerTargetMeta{LoadbalancerID: "lb1"},
+			})
+			Expect(err).To(MatchError("loadbalancer target of lb1 has no target_ip"))
+
+			_, err = ObjectKeyFromObject(&api.NeighborNat{Spec: api.NeighborNatSpec{Vni: 100}})
+			Expect(err).To(MatchError("neighbor nat of vni 100 has no nat_ip"))
+
+			_, err = ObjectKeyFromObject(&api.Route{RouteMeta: api.RouteMeta{VNI: 100}})
+			Expect(err).To(MatchError("route of vni 100 has no prefix"))
+		})
+	})
+
+	Context("ParseObjectKey", func() {
+		DescribeTable("should round-trip String",
+			func(kind string, key ObjectKey) {
+				parsed, err := ParseObjectKey(kind, key.String())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parsed).To(Equal(key))
+			},
+			Entry("interface", api.InterfaceKind, InterfaceKey{ID: "vm-1"}),
+			Entry("prefix", api.PrefixKind, PrefixKey{InterfaceID: "vm-1", Prefix: netip.MustParsePrefix("10.0.0.0/24")}),
+			Entry("virtual ip", api.VirtualIPKind, VirtualIPKey{InterfaceID: "vm-1"}),
+			Entry("route", api.RouteKind, RouteKey{
+				VNI:        100,
+				Prefix:     netip.MustParsePrefix("10.0.0.0/24"),
+				NextHopVNI: 0,
+				NextHopIP:  netip.MustParseAddr("fc00::1"),
+			}),
+			Entry("route without next hop", api.RouteKind, RouteKey{VNI: 100, Prefix: netip.MustParsePrefix("fd00::/64")}),
+			Entry("loadbalancer", api.LoadBalancerKind, LoadBalancerKey{ID: "lb-1"}),
+			Entry("loadbalancer prefix", api.LoadBalancerPrefixKind, LoadBalancerPrefixKey{
+				InterfaceID: "vm-1",
+				Prefix:      netip.MustParsePrefix("10.0.0.0/32"),
+			}),
+			Entry("loadbalancer target", api.LoadBalancerTargetKind, LoadBalancerTargetKey{
+				LoadBalancerID: "lb-1",
+				TargetIP:       netip.MustParseAddr("fc00::2"),
+			}),
+			Entry("nat", api.NatKind, NatKey{InterfaceID: "vm-1"}),
+			Entry("neighbor nat", api.NeighborNatKind, NeighborNatKey{
+				NatIP:   netip.MustParseAddr("10.20.30.40"),
+				Vni:     100,
+				MinPort: 1000,
+				MaxPort: 2000,
+			}),
+			Entry("firewall rule", api.FirewallRuleKind, FirewallRuleKey{InterfaceID: "vm-1", RuleID: "r-12"}),
+		)
+
+		It("should parse the documented route form", func() {
+			key, err := ParseObjectKey(api.RouteKind, "100:10.0.0.0/24-0:fc00::1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key).To(Equal(RouteKey{
+				VNI:       100,
+				Prefix:    netip.MustParsePrefix("10.0.0.0/24"),
+				NextHopIP: netip.MustParseAddr("fc00::1"),
+			}))
+		})
+
+		DescribeTable("should reject malformed keys",
+			func(kind, s string) {
+				_, err := ParseObjectKey(kind, s)
+				Expect(err).To(HaveOccurred())
+			},
+			Entry("empty", api.InterfaceKind, ""),
+			Entry("prefix without interface", api.PrefixKind, "10.0.0.0/24"),
+			Entry("route without vni", api.RouteKind, "10.0.0.0/24"),
+			Entry("route with bad next hop", api.RouteKind, "100:10.0.0.0/24-0"),
+			Entry("neighbor nat without ports", api.NeighborNatKind, "100-10.20.30.40"),
+			Entry("firewall rule without rule", api.FirewallRuleKind, "vm1"),
+			Entry("unknown kind", "Unknown", "foo"),
+		)
+	})
+})
@@ -18,10 +18,15 @@ type Decoder interface {
 }
 
 type KindDecoder struct {
-	scheme  *Scheme
-	decoder PeekDecoder
+	scheme   *Scheme
+	decoder  PeekDecoder
+	rawSpecs RawSpecs
 }
 
+// RawSpecs maps decoded objects to their spec as read from the source, before it was decoded
+// into the object. It tells which fields were set in the source, even if set to their zero value.
+type RawSpecs map[any]any
+
 func NewKindDecoder(scheme *Scheme, decoder PeekDecoder) *KindDecoder {
 	return &KindDecoder{
 		scheme:  scheme,
@@ -29,6 +34,11 @@ func NewKindDecoder(scheme *Scheme, decoder PeekDecoder) *KindDecoder {
 	}
 }
 
+// RecordRawSpecs makes Next store the spec of every decoded object as read from the source in rawSpecs.
+func (d *KindDecoder) RecordRawSpecs(rawSpecs RawSpecs) {
+	d.rawSpecs = rawSpecs
+}
+
 type PeekDecoder interface {
 	Decoder
 	Undecode() error
@@ -86,6 +96,9 @@ func (d *KindDecoder) Next() (any, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error unmarshaling %s: %w", obj.Kind, err)
 	}
+	if d.rawSpecs != nil && obj.Spec != nil {
+		d.rawSpecs[res] = obj.Spec
+	}
 
 	return res, nil
 }
@@ -28,4 +28,19 @@ func init() {
 	); err != nil {
 		panic(err)
 	}
+
+	// routes depend on interfaces and loadbalancers, as dpservice rejects routes of an unused VNI
+	for kind, dependsOn := range map[string][]string{
+		api.PrefixKind:             {api.InterfaceKind},
+		api.VirtualIPKind:          {api.InterfaceKind},
+		api.NatKind:                {api.InterfaceKind},
+		api.FirewallRuleKind:       {api.InterfaceKind},
+		api.LoadBalancerPrefixKind: {api.InterfaceKind},
+		api.LoadBalancerTargetKind: {api.LoadBalancerKind},
+		api.RouteKind:              {api.InterfaceKind, api.LoadBalancerKind},
+	} {
+		if err := DefaultScheme.AddDependency(kind, dependsOn...); err != nil {
+			panic(err)
+		}
+	}
 }
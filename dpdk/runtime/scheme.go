@@ -6,17 +6,20 @@ package runtime
 import (
 	"fmt"
 	"reflect"
+	"sort"
 )
 
 type Scheme struct {
-	typeByKind map[string]reflect.Type
-	kindByType map[reflect.Type]string
+	typeByKind   map[string]reflect.Type
+	kindByType   map[reflect.Type]string
+	dependencies map[string][]string
 }
 
 func NewScheme() *Scheme {
 	return &Scheme{
-		typeByKind: make(map[string]reflect.Type),
-		kindByType: make(map[reflect.Type]string),
+		typeByKind:   make(map[string]reflect.Type),
+		kindByType:   make(map[reflect.Type]string),
+		dependencies: make(map[string][]string),
 	}
 }
 
@@ -66,3 +69,150 @@ func (s *Scheme) New(name string) (any, error) {
 	}
 	return reflect.New(typ).Interface(), nil
 }
+
+// Kinds returns the names of all registered kinds in alphabetical order.
+func (s *Scheme) Kinds() []string {
+	kinds := make([]string, 0, len(s.typeByKind))
+	for kind := range s.typeByKind {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// AddDependency records that objects of kind can only be created after objects of the dependsOn kinds.
+func (s *Scheme) AddDependency(kind string, dependsOn ...string) error {
+	for _, name := range append([]string{kind}, dependsOn...) {
+		if _, ok := s.typeByKind[name]; !ok {
+			return fmt.Errorf("no type %q registered", name)
+		}
+	}
+	s.dependencies[kind] = append(s.dependencies[kind], dependsOn...)
+	return nil
+}
+
+// CreationOrder returns all registered kinds sorted so that every kind comes after the kinds it depends on.
+// Kinds without dependencies between them are sorted alphabetically.
+func (s *Scheme) CreationOrder() ([]string, error) {
+	dependents := make(map[string][]string)
+	pending := make(map[string]int)
+	for kind := range s.typeByKind {
+		pending[kind] = len(s.dependencies[kind])
+		for _, dependency := range s.dependencies[kind] {
+			dependents[dependency] = append(dependents[dependency], kind)
+		}
+	}
+
+	var ready []string
+	for kind, n := range pending {
+		if n == 0 {
+			ready = append(ready, kind)
+		}
+	}
+
+	order := make([]string, 0, len(pending))
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		kind := ready[0]
+		ready = ready[1:]
+		order = append(order, kind)
+
+		for _, dependent := range dependents[kind] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(order) != len(pending) {
+		return nil, fmt.Errorf("dependency cycle between kinds")
+	}
+	return order, nil
+}
+
+// SortForCreation stably sorts objs so that objects come after the objects of the kinds they depend on.
+func (s *Scheme) SortForCreation(objs []any) error {
+	return s.sortByOrder(objs, false)
+}
+
+// SortForDeletion stably sorts objs so that objects come before the objects of the kinds they depend on.
+func (s *Scheme) SortForDeletion(objs []any) error {
+	return s.sortByOrder(objs, true)
+}
+
+func (s *Scheme) sortByOrder(objs []any, reverse bool) error {
+	order, err := s.CreationOrder()
+	if err != nil {
+		return err
+	}
+	rankByKind := make(map[string]int, len(order))
+	for i, kind := range order {
+		rankByKind[kind] = i
+		if reverse {
+			rankByKind[kind] = len(order) - i
+		}
+	}
+
+	ranks := make(map[any]int, len(objs))
+	for _, obj := range objs {
+		kind, err := s.KindFor(obj)
+		if err != nil {
+			return err
+		}
+		ranks[obj] = rankByKind[kind]
+	}
+
+	sort.SliceStable(objs, func(i, j int) bool {
+		return ranks[objs[i]] < ranks[objs[j]]
+	})
+	return nil
+}
+
+// CreationLevels groups objs by the dependency depth of their kind. Objects of a level only depend
+// on objects of previous levels, so the objects of one level can be created concurrently.
+// Objects keep their relative order within a level.
+func (s *Scheme) CreationLevels(objs []any) ([][]any, error) {
+	order, err := s.CreationOrder()
+	if err != nil {
+		return nil, err
+	}
+	depthByKind := make(map[string]int, len(order))
+	maxDepth := 0
+	for _, kind := range order {
+		depth := 0
+		for _, dependency := range s.dependencies[kind] {
+			if depthByKind[dependency]+1 > depth {
+				depth = depthByKind[dependency] + 1
+			}
+		}
+		depthByKind[kind] = depth
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+	}
+
+	levels := make([][]any, maxDepth+1)
+	for _, obj := range objs {
+		kind, err := s.KindFor(obj)
+		if err != nil {
+			return nil, err
+		}
+		depth := depthByKind[kind]
+		levels[depth] = append(levels[depth], obj)
+	}
+	return levels, nil
+}
+
+// DeletionLevels is like CreationLevels, but in reverse order so that objects are deleted
+// before the objects they depend on.
+func (s *Scheme) DeletionLevels(objs []any) ([][]any, error) {
+	levels, err := s.CreationLevels(objs)
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(levels)-1; i < j; i, j = i+1, j-1 {
+		levels[i], levels[j] = levels[j], levels[i]
+	}
+	return levels, nil
+}
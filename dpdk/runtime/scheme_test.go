@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime_test
+
+import (
+	. "github.com/ironcore-dev/dpservice-cli/dpdk/runtime"
+	"github.com/ironcore-dev/dpservice-go/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scheme", func() {
+	Context("Dependencies", func() {
+		var (
+			iface    *api.Interface
+			prefix   *api.Prefix
+			fwrule   *api.FirewallRule
+			lb       *api.LoadBalancer
+			lbtarget *api.LoadBalancerTarget
+		)
+		BeforeEach(func() {
+			iface = &api.Interface{InterfaceMeta: api.InterfaceMeta{ID: "vm1"}}
+			prefix = &api.Prefix{PrefixMeta: api.PrefixMeta{InterfaceID: "vm1"}}
+			fwrule = &api.FirewallRule{FirewallRuleMeta: api.FirewallRuleMeta{InterfaceID: "vm1"}}
+			lb = &api.LoadBalancer{LoadBalancerMeta: api.LoadBalancerMeta{ID: "lb1"}}
+			lbtarget = &api.LoadBalancerTarget{LoadBalancerTargetMeta: api.LoadBalancerTargetMeta{LoadbalancerID: "lb1"}}
+		})
+
+		indexOf := func(objs []any, obj any) int {
+			for i := range objs {
+				if objs[i] == obj {
+					return i
+				}
+			}
+			return -1
+		}
+
+		It("should sort objects after the objects they depend on", func() {
+			objs := []any{fwrule, lbtarget, prefix, iface, lb}
+			Expect(DefaultScheme.SortForCreation(objs)).To(Succeed())
+			Expect(indexOf(objs, iface)).To(BeNumerically("<", indexOf(objs, prefix)))
+			Expect(indexOf(objs, iface)).To(BeNumerically("<", indexOf(objs, fwrule)))
+			Expect(indexOf(objs, lb)).To(BeNumerically("<", indexOf(objs, lbtarget)))
+		})
+
+		It("should sort objects before the objects they depend on for deletion", func() {
+			objs := []any{iface, lb, prefix, lbtarget, fwrule}
+			Expect(DefaultScheme.SortForDeletion(objs)).To(Succeed())
+			Expect(indexOf(objs, prefix)).To(BeNumerically("<", indexOf(objs, iface)))
+			Expect(indexOf(objs, fwrule)).To(BeNumerically("<", indexOf(objs, iface)))
+			Expect(indexOf(objs, lbtarget)).To(BeNumerically("<", indexOf(objs, lb)))
+		})
+
+		It("should keep the input order within a kind", func() {
+			other := &api.Prefix{PrefixMeta: api.PrefixMeta{InterfaceID: "vm2"}}
+			objs := []any{prefix, other, iface}
+			Expect(DefaultScheme.SortForCreation(objs)).To(Succeed())
+			Expect(objs).To(Equal([]any{iface, prefix, other}))
+		})
+
+		It("should group objects into levels by dependency depth", func() {
+			route := &api.Route{RouteMeta: api.RouteMeta{VNI: 100}}
+			levels, err := DefaultScheme.CreationLevels([]any{fwrule, route, lbtarget, iface, prefix, lb})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(levels).To(Equal([][]any{{iface, lb}, {fwrule, route, lbtarget, prefix}}))
+
+			levels, err = DefaultScheme.DeletionLevels([]any{iface, prefix})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(levels).To(Equal([][]any{{prefix}, {iface}}))
+		})
+
+		It("should create routes after the interfaces and loadbalancers creating their VNI", func() {
+			route := &api.Route{RouteMeta: api.RouteMeta{VNI: 100}}
+			levels, err := DefaultScheme.CreationLevels([]any{route, lb, iface})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(levels).To(Equal([][]any{{lb, iface}, {route}}))
+
+			levels, err = DefaultScheme.DeletionLevels([]any{route, lb, iface})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(levels).To(Equal([][]any{{route}, {lb, iface}}))
+		})
+
+		It("should detect dependency cycles", func() {
+			scheme := NewScheme()
+			Expect(scheme.Add(&api.Interface{}, &api.Prefix{})).To(Succeed())
+			Expect(scheme.AddDependency(api.PrefixKind, api.InterfaceKind)).To(Succeed())
+			Expect(scheme.AddDependency(api.InterfaceKind, api.PrefixKind)).To(Succeed())
+			_, err := scheme.CreationOrder()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package fake
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/ironcore-dev/dpservice-go/client"
+	dpdkproto "github.com/ironcore-dev/dpservice-go/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const bufconnSize = 1024 * 1024
+
+// Serve serves the fake on the listener until Stop is called or the listener fails.
+func (s *Server) Serve(lis net.Listener) error {
+	server := grpc.NewServer()
+	dpdkproto.RegisterDPDKironcoreServer(server, s)
+	return server.Serve(lis)
+}
+
+// NewClient returns a client connected to the fake in process, so a Server can be used wherever
+// a client factory is expected. The first call starts serving in the background.
+func (s *Server) NewClient(ctx context.Context) (client.Client, func() error, error) {
+	lis := s.bufconnListener()
+	conn, err := grpc.DialContext(ctx, "bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error connecting to fake dpservice: %w", err)
+	}
+	return client.NewClient(dpdkproto.NewDPDKironcoreClient(conn)), conn.Close, nil
+}
+
+func (s *Server) bufconnListener() *bufconn.Listener {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener == nil {
+		s.listener = bufconn.Listen(bufconnSize)
+		s.grpcServer = grpc.NewServer()
+		dpdkproto.RegisterDPDKironcoreServer(s.grpcServer, s)
+		go func() { _ = s.grpcServer.Serve(s.listener) }()
+	}
+	return s.listener
+}
+
+// Stop stops serving the connections of NewClient.
+func (s *Server) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.grpcServer != nil {
+		s.grpcServer.Stop()
+		s.grpcServer, s.listener = nil, nil
+	}
+}
@@ -0,0 +1,781 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package fake implements dpservice in memory for tests and demos. It keeps track of the objects
+// created through the gRPC API and answers with the error codes of dpservice-go/errors, but does
+// not touch any network traffic.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ironcore-dev/dpservice-go/errors"
+	dpdkproto "github.com/ironcore-dev/dpservice-go/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// ServiceVersion is the version the fake reports for dpservice.
+const ServiceVersion = "fake"
+
+type iface struct {
+	proto      *dpdkproto.Interface
+	prefixes   map[string]*dpdkproto.Prefix
+	lbPrefixes map[string]*dpdkproto.Prefix
+	vip        *dpdkproto.GetVipResponse
+	nat        *dpdkproto.GetNatResponse
+	fwRules    map[string]*dpdkproto.FirewallRule
+}
+
+type loadBalancer struct {
+	proto   *dpdkproto.GetLoadBalancerResponse
+	targets map[string]*dpdkproto.IpAddress
+}
+
+// Server is an in-memory dpservice. The zero value is not usable, use NewServer.
+type Server struct {
+	dpdkproto.UnimplementedDPDKironcoreServer
+
+	mu sync.Mutex
+
+	uuid          string
+	interfaces    map[string]*iface
+	loadBalancers map[string]*loadBalancer
+	// routes are the routes by VNI and prefix
+	routes       map[uint32]map[string]*dpdkproto.Route
+	neighborNats map[string]*dpdkproto.NatEntry
+	capture      *dpdkproto.CaptureConfig
+	// underlayRoutes counts the underlay routes handed out so far
+	underlayRoutes uint32
+
+	// grpcServer and listener serve the in-process connections of NewClient
+	grpcServer *grpc.Server
+	listener   *bufconn.Listener
+}
+
+// NewServer returns an empty, uninitialized dpservice.
+func NewServer() *Server {
+	return &Server{
+		interfaces:    make(map[string]*iface),
+		loadBalancers: make(map[string]*loadBalancer),
+		routes:        make(map[uint32]map[string]*dpdkproto.Route),
+		neighborNats:  make(map[string]*dpdkproto.NatEntry),
+	}
+}
+
+func okStatus() *dpdkproto.Status {
+	return &dpdkproto.Status{}
+}
+
+func errStatus(code uint32, format string, args ...any) *dpdkproto.Status {
+	return &dpdkproto.Status{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// nextUnderlayRoute returns a new underlay route, as dpservice assigns one to every routable object.
+func (s *Server) nextUnderlayRoute() []byte {
+	s.underlayRoutes++
+	return []byte(fmt.Sprintf("fc00:1::%x", s.underlayRoutes))
+}
+
+func ipString(ip *dpdkproto.IpAddress) string {
+	return string(ip.GetAddress())
+}
+
+func prefixKey(prefix *dpdkproto.Prefix) string {
+	return fmt.Sprintf("%s/%d", ipString(prefix.GetIp()), prefix.GetLength())
+}
+
+func validIP(ip *dpdkproto.IpAddress) bool {
+	_, err := netip.ParseAddr(ipString(ip))
+	return err == nil
+}
+
+func validPrefix(prefix *dpdkproto.Prefix) bool {
+	_, err := netip.ParsePrefix(prefixKey(prefix))
+	return err == nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *Server) CheckInitialized(_ context.Context, _ *dpdkproto.CheckInitializedRequest) (*dpdkproto.CheckInitializedResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.uuid == "" {
+		return &dpdkproto.CheckInitializedResponse{Status: errStatus(errors.NOT_ACTIVE, "not initialized")}, nil
+	}
+	return &dpdkproto.CheckInitializedResponse{Status: okStatus(), Uuid: s.uuid}, nil
+}
+
+func (s *Server) Initialize(_ context.Context, _ *dpdkproto.InitializeRequest) (*dpdkproto.InitializeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.uuid == "" {
+		s.uuid = "00000000-0000-4000-8000-000000000001"
+	}
+	return &dpdkproto.InitializeResponse{Status: okStatus(), Uuid: s.uuid}, nil
+}
+
+func (s *Server) GetVersion(_ context.Context, _ *dpdkproto.GetVersionRequest) (*dpdkproto.GetVersionResponse, error) {
+	return &dpdkproto.GetVersionResponse{
+		Status:          okStatus(),
+		ServiceProtocol: strings.TrimSpace(dpdkproto.GeneratedFrom),
+		ServiceVersion:  ServiceVersion,
+	}, nil
+}
+
+func (s *Server) ListInterfaces(_ context.Context, _ *dpdkproto.ListInterfacesRequest) (*dpdkproto.ListInterfacesResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := &dpdkproto.ListInterfacesResponse{Status: okStatus()}
+	for _, id := range sortedKeys(s.interfaces) {
+		res.Interfaces = append(res.Interfaces, s.interfaces[id].proto)
+	}
+	return res, nil
+}
+
+func (s *Server) GetInterface(_ context.Context, req *dpdkproto.GetInterfaceRequest) (*dpdkproto.GetInterfaceResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	iface, ok := s.interfaces[string(req.GetInterfaceId())]
+	if !ok {
+		return &dpdkproto.GetInterfaceResponse{Status: errStatus(errors.NOT_FOUND, "interface not found")}, nil
+	}
+	return &dpdkproto.GetInterfaceResponse{Status: okStatus(), Interface: iface.proto}, nil
+}
+
+func (s *Server) CreateInterface(_ context.Context, req *dpdkproto.CreateInterfaceRequest) (*dpdkproto.CreateInterfaceResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := string(req.GetInterfaceId())
+	if id == "" {
+		return &dpdkproto.CreateInterfaceResponse{Status: errStatus(errors.BAD_REQUEST, "interface id is required")}, nil
+	}
+	if _, ok := s.interfaces[id]; ok {
+		return &dpdkproto.CreateInterfaceResponse{Status: errStatus(errors.ALREADY_EXISTS, "interface already exists")}, nil
+	}
+
+	ipv4, ipv6 := string(req.GetIpv4Config().GetPrimaryAddress()), string(req.GetIpv6Config().GetPrimaryAddress())
+	if ipv4 == "" {
+		ipv4 = "0.0.0.0"
+	}
+	if ipv6 == "" {
+		ipv6 = "::"
+	}
+	for _, ip := range []string{ipv4, ipv6} {
+		if _, err := netip.ParseAddr(ip); err != nil {
+			return &dpdkproto.CreateInterfaceResponse{Status: errStatus(errors.BAD_IPVER, "invalid ip %q", ip)}, nil
+		}
+	}
+
+	device := req.GetDeviceName()
+	if device == "" {
+		device = fmt.Sprintf("net_tap%d", len(s.interfaces))
+	}
+	underlayRoute := s.nextUnderlayRoute()
+	s.interfaces[id] = &iface{
+		proto: &dpdkproto.Interface{
+			Id:             []byte(id),
+			Vni:            req.GetVni(),
+			PrimaryIpv4:    []byte(ipv4),
+			PrimaryIpv6:    []byte(ipv6),
+			UnderlayRoute:  underlayRoute,
+			PciName:        device,
+			MeteringParams: req.GetMeteringParameters(),
+		},
+		prefixes:   make(map[string]*dpdkproto.Prefix),
+		lbPrefixes: make(map[string]*dpdkproto.Prefix),
+		fwRules:    make(map[string]*dpdkproto.FirewallRule),
+	}
+	return &dpdkproto.CreateInterfaceResponse{
+		Status:        okStatus(),
+		UnderlayRoute: underlayRoute,
+		Vf:            &dpdkproto.VirtualFunction{Name: device},
+	}, nil
+}
+
+func (s *Server) DeleteInterface(_ context.Context, req *dpdkproto.DeleteInterfaceRequest) (*dpdkproto.DeleteInterfaceResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := string(req.GetInterfaceId())
+	iface, ok := s.interfaces[id]
+	if !ok {
+		return &dpdkproto.DeleteInterfaceResponse{Status: errStatus(errors.NOT_FOUND, "interface not found")}, nil
+	}
+	delete(s.interfaces, id)
+
+	// the routes of a VNI are freed with its last interface
+	vni := iface.proto.GetVni()
+	if !s.vniInUse(vni) {
+		delete(s.routes, vni)
+	}
+	return &dpdkproto.DeleteInterfaceResponse{Status: okStatus()}, nil
+}
+
+func (s *Server) vniInUse(vni uint32) bool {
+	for _, iface := range s.interfaces {
+		if iface.proto.GetVni() == vni {
+			return true
+		}
+	}
+	for _, lb := range s.loadBalancers {
+		if lb.proto.GetVni() == vni {
+			return true
+		}
+	}
+	return false
+}
+
+// prefixes returns the prefixes or load balancer prefixes of an interface.
+func (s *Server) prefixes(interfaceID []byte, lb bool) (map[string]*dpdkproto.Prefix, *dpdkproto.Status) {
+	iface, ok := s.interfaces[string(interfaceID)]
+	if !ok {
+		return nil, errStatus(errors.NO_VM, "interface not found")
+	}
+	if lb {
+		return iface.lbPrefixes, nil
+	}
+	return iface.prefixes, nil
+}
+
+func (s *Server) listPrefixes(interfaceID []byte, lb bool) ([]*dpdkproto.Prefix, *dpdkproto.Status) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prefixes, status := s.prefixes(interfaceID, lb)
+	if status != nil {
+		return nil, status
+	}
+	var res []*dpdkproto.Prefix
+	for _, key := range sortedKeys(prefixes) {
+		res = append(res, prefixes[key])
+	}
+	return res, okStatus()
+}
+
+func (s *Server) createPrefix(interfaceID []byte, prefix *dpdkproto.Prefix, lb bool) ([]byte, *dpdkproto.Status) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prefixes, status := s.prefixes(interfaceID, lb)
+	if status != nil {
+		return nil, status
+	}
+	if !validPrefix(prefix) {
+		return nil, errStatus(errors.BAD_REQUEST, "invalid prefix %s", prefixKey(prefix))
+	}
+	key := prefixKey(prefix)
+	if _, ok := prefixes[key]; ok {
+		return nil, errStatus(errors.ROUTE_EXISTS, "prefix already exists")
+	}
+
+	underlayRoute := s.nextUnderlayRoute()
+	prefixes[key] = &dpdkproto.Prefix{Ip: prefix.GetIp(), Length: prefix.GetLength(), UnderlayRoute: underlayRoute}
+	return underlayRoute, okStatus()
+}
+
+func (s *Server) deletePrefix(interfaceID []byte, prefix *dpdkproto.Prefix, lb bool) *dpdkproto.Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prefixes, status := s.prefixes(interfaceID, lb)
+	if status != nil {
+		return status
+	}
+	key := prefixKey(prefix)
+	if _, ok := prefixes[key]; !ok {
+		return errStatus(errors.ROUTE_NOT_FOUND, "prefix not found")
+	}
+	delete(prefixes, key)
+	return okStatus()
+}
+
+func (s *Server) ListPrefixes(_ context.Context, req *dpdkproto.ListPrefixesRequest) (*dpdkproto.ListPrefixesResponse, error) {
+	prefixes, status := s.listPrefixes(req.GetInterfaceId(), false)
+	return &dpdkproto.ListPrefixesResponse{Status: status, Prefixes: prefixes}, nil
+}
+
+func (s *Server) CreatePrefix(_ context.Context, req *dpdkproto.CreatePrefixRequest) (*dpdkproto.CreatePrefixResponse, error) {
+	underlayRoute, status := s.createPrefix(req.GetInterfaceId(), req.GetPrefix(), false)
+	return &dpdkproto.CreatePrefixResponse{Status: status, UnderlayRoute: underlayRoute}, nil
+}
+
+func (s *Server) DeletePrefix(_ context.Context, req *dpdkproto.DeletePrefixRequest) (*dpdkproto.DeletePrefixResponse, error) {
+	return &dpdkproto.DeletePrefixResponse{Status: s.deletePrefix(req.GetInterfaceId(), req.GetPrefix(), false)}, nil
+}
+
+func (s *Server) ListLoadBalancerPrefixes(_ context.Context, req *dpdkproto.ListLoadBalancerPrefixesRequest) (*dpdkproto.ListLoadBalancerPrefixesResponse, error) {
+	prefixes, status := s.listPrefixes(req.GetInterfaceId(), true)
+	return &dpdkproto.ListLoadBalancerPrefixesResponse{Status: status, Prefixes: prefixes}, nil
+}
+
+func (s *Server) CreateLoadBalancerPrefix(_ context.Context, req *dpdkproto.CreateLoadBalancerPrefixRequest) (*dpdkproto.CreateLoadBalancerPrefixResponse, error) {
+	underlayRoute, status := s.createPrefix(req.GetInterfaceId(), req.GetPrefix(), true)
+	return &dpdkproto.CreateLoadBalancerPrefixResponse{Status: status, UnderlayRoute: underlayRoute}, nil
+}
+
+func (s *Server) DeleteLoadBalancerPrefix(_ context.Context, req *dpdkproto.DeleteLoadBalancerPrefixRequest) (*dpdkproto.DeleteLoadBalancerPrefixResponse, error) {
+	return &dpdkproto.DeleteLoadBalancerPrefixResponse{Status: s.deletePrefix(req.GetInterfaceId(), req.GetPrefix(), true)}, nil
+}
+
+func (s *Server) CreateVip(_ context.Context, req *dpdkproto.CreateVipRequest) (*dpdkproto.CreateVipResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	iface, ok := s.interfaces[string(req.GetInterfaceId())]
+	if !ok {
+		return &dpdkproto.CreateVipResponse{Status: errStatus(errors.NO_VM, "interface not found")}, nil
+	}
+	if iface.vip != nil {
+		return &dpdkproto.CreateVipResponse{Status: errStatus(errors.SNAT_EXISTS, "virtual ip already exists")}, nil
+	}
+	if !validIP(req.GetVipIp()) {
+		return &dpdkproto.CreateVipResponse{Status: errStatus(errors.BAD_REQUEST, "invalid virtual ip")}, nil
+	}
+
+	underlayRoute := s.nextUnderlayRoute()
+	iface.vip = &dpdkproto.GetVipResponse{Status: okStatus(), VipIp: req.GetVipIp(), UnderlayRoute: underlayRoute}
+	return &dpdkproto.CreateVipResponse{Status: okStatus(), UnderlayRoute: underlayRoute}, nil
+}
+
+func (s *Server) GetVip(_ context.Context, req *dpdkproto.GetVipRequest) (*dpdkproto.GetVipResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	iface, ok := s.interfaces[string(req.GetInterfaceId())]
+	if !ok {
+		return &dpdkproto.GetVipResponse{Status: errStatus(errors.NO_VM, "interface not found")}, nil
+	}
+	if iface.vip == nil {
+		return &dpdkproto.GetVipResponse{Status: errStatus(errors.SNAT_NO_DATA, "virtual ip not found")}, nil
+	}
+	return iface.vip, nil
+}
+
+func (s *Server) DeleteVip(_ context.Context, req *dpdkproto.DeleteVipRequest) (*dpdkproto.DeleteVipResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	iface, ok := s.interfaces[string(req.GetInterfaceId())]
+	if !ok {
+		return &dpdkproto.DeleteVipResponse{Status: errStatus(errors.NO_VM, "interface not found")}, nil
+	}
+	if iface.vip == nil {
+		return &dpdkproto.DeleteVipResponse{Status: errStatus(errors.SNAT_NO_DATA, "virtual ip not found")}, nil
+	}
+	iface.vip = nil
+	return &dpdkproto.DeleteVipResponse{Status: okStatus()}, nil
+}
+
+func (s *Server) CreateLoadBalancer(_ context.Context, req *dpdkproto.CreateLoadBalancerRequest) (*dpdkproto.CreateLoadBalancerResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := string(req.GetLoadbalancerId())
+	if id == "" {
+		return &dpdkproto.CreateLoadBalancerResponse{Status: errStatus(errors.BAD_REQUEST, "loadbalancer id is required")}, nil
+	}
+	if _, ok := s.loadBalancers[id]; ok {
+		return &dpdkproto.CreateLoadBalancerResponse{Status: errStatus(errors.ALREADY_EXISTS, "loadbalancer already exists")}, nil
+	}
+	if !validIP(req.GetLoadbalancedIp()) {
+		return &dpdkproto.CreateLoadBalancerResponse{Status: errStatus(errors.BAD_REQUEST, "invalid loadbalanced ip")}, nil
+	}
+
+	underlayRoute := s.nextUnderlayRoute()
+	s.loadBalancers[id] = &loadBalancer{
+		proto: &dpdkproto.GetLoadBalancerResponse{
+			Status:            okStatus(),
+			LoadbalancedIp:    req.GetLoadbalancedIp(),
+			Vni:               req.GetVni(),
+			LoadbalancedPorts: req.GetLoadbalancedPorts(),
+			UnderlayRoute:     underlayRoute,
+		},
+		targets: make(map[string]*dpdkproto.IpAddress),
+	}
+	return &dpdkproto.CreateLoadBalancerResponse{Status: okStatus(), UnderlayRoute: underlayRoute}, nil
+}
+
+func (s *Server) GetLoadBalancer(_ context.Context, req *dpdkproto.GetLoadBalancerRequest) (*dpdkproto.GetLoadBalancerResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lb, ok := s.loadBalancers[string(req.GetLoadbalancerId())]
+	if !ok {
+		return &dpdkproto.GetLoadBalancerResponse{Status: errStatus(errors.NOT_FOUND, "loadbalancer not found")}, nil
+	}
+	return lb.proto, nil
+}
+
+func (s *Server) DeleteLoadBalancer(_ context.Context, req *dpdkproto.DeleteLoadBalancerRequest) (*dpdkproto.DeleteLoadBalancerResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := string(req.GetLoadbalancerId())
+	if _, ok := s.loadBalancers[id]; !ok {
+		return &dpdkproto.DeleteLoadBalancerResponse{Status: errStatus(errors.NOT_FOUND, "loadbalancer not found")}, nil
+	}
+	delete(s.loadBalancers, id)
+	return &dpdkproto.DeleteLoadBalancerResponse{Status: okStatus()}, nil
+}
+
+func (s *Server) CreateLoadBalancerTarget(_ context.Context, req *dpdkproto.CreateLoadBalancerTargetRequest) (*dpdkproto.CreateLoadBalancerTargetResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lb, ok := s.loadBalancers[string(req.GetLoadbalancerId())]
+	if !ok {
+		return &dpdkproto.CreateLoadBalancerTargetResponse{Status: errStatus(errors.NO_LB, "loadbalancer not found")}, nil
+	}
+	if !validIP(req.GetTargetIp()) {
+		return &dpdkproto.CreateLoadBalancerTargetResponse{Status: errStatus(errors.BAD_REQUEST, "invalid target ip")}, nil
+	}
+	key := ipString(req.GetTargetIp())
+	if _, ok := lb.targets[key]; ok {
+		return &dpdkproto.CreateLoadBalancerTargetResponse{Status: errStatus(errors.ALREADY_EXISTS, "target already exists")}, nil
+	}
+	lb.targets[key] = req.GetTargetIp()
+	return &dpdkproto.CreateLoadBalancerTargetResponse{Status: okStatus()}, nil
+}
+
+func (s *Server) ListLoadBalancerTargets(_ context.Context, req *dpdkproto.ListLoadBalancerTargetsRequest) (*dpdkproto.ListLoadBalancerTargetsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lb, ok := s.loadBalancers[string(req.GetLoadbalancerId())]
+	if !ok {
+		return &dpdkproto.ListLoadBalancerTargetsResponse{Status: errStatus(errors.NO_LB, "loadbalancer not found")}, nil
+	}
+	res := &dpdkproto.ListLoadBalancerTargetsResponse{Status: okStatus()}
+	for _, key := range sortedKeys(lb.targets) {
+		res.TargetIps = append(res.TargetIps, lb.targets[key])
+	}
+	return res, nil
+}
+
+func (s *Server) DeleteLoadBalancerTarget(_ context.Context, req *dpdkproto.DeleteLoadBalancerTargetRequest) (*dpdkproto.DeleteLoadBalancerTargetResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lb, ok := s.loadBalancers[string(req.GetLoadbalancerId())]
+	if !ok {
+		return &dpdkproto.DeleteLoadBalancerTargetResponse{Status: errStatus(errors.NO_LB, "loadbalancer not found")}, nil
+	}
+	key := ipString(req.GetTargetIp())
+	if _, ok := lb.targets[key]; !ok {
+		return &dpdkproto.DeleteLoadBalancerTargetResponse{Status: errStatus(errors.NOT_FOUND, "target not found")}, nil
+	}
+	delete(lb.targets, key)
+	return &dpdkproto.DeleteLoadBalancerTargetResponse{Status: okStatus()}, nil
+}
+
+func (s *Server) CreateNat(_ context.Context, req *dpdkproto.CreateNatRequest) (*dpdkproto.CreateNatResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	iface, ok := s.interfaces[string(req.GetInterfaceId())]
+	if !ok {
+		return &dpdkproto.CreateNatResponse{Status: errStatus(errors.NO_VM, "interface not found")}, nil
+	}
+	if iface.nat != nil {
+		return &dpdkproto.CreateNatResponse{Status: errStatus(errors.SNAT_EXISTS, "nat already exists")}, nil
+	}
+	if !validIP(req.GetNatIp()) {
+		return &dpdkproto.CreateNatResponse{Status: errStatus(errors.BAD_REQUEST, "invalid nat ip")}, nil
+	}
+	if req.GetMinPort() >= req.GetMaxPort() {
+		return &dpdkproto.CreateNatResponse{Status: errStatus(errors.BAD_REQUEST, "min port has to be lower than max port")}, nil
+	}
+
+	underlayRoute := s.nextUnderlayRoute()
+	iface.nat = &dpdkproto.GetNatResponse{
+		Status:        okStatus(),
+		NatIp:         req.GetNatIp(),
+		MinPort:       req.GetMinPort(),
+		MaxPort:       req.GetMaxPort(),
+		UnderlayRoute: underlayRoute,
+	}
+	return &dpdkproto.CreateNatResponse{Status: okStatus(), UnderlayRoute: underlayRoute}, nil
+}
+
+func (s *Server) GetNat(_ context.Context, req *dpdkproto.GetNatRequest) (*dpdkproto.GetNatResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	iface, ok := s.interfaces[string(req.GetInterfaceId())]
+	if !ok {
+		return &dpdkproto.GetNatResponse{Status: errStatus(errors.NO_VM, "interface not found")}, nil
+	}
+	if iface.nat == nil {
+		return &dpdkproto.GetNatResponse{Status: errStatus(errors.SNAT_NO_DATA, "nat not found")}, nil
+	}
+	return iface.nat, nil
+}
+
+func (s *Server) DeleteNat(_ context.Context, req *dpdkproto.DeleteNatRequest) (*dpdkproto.DeleteNatResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	iface, ok := s.interfaces[string(req.GetInterfaceId())]
+	if !ok {
+		return &dpdkproto.DeleteNatResponse{Status: errStatus(errors.NO_VM, "interface not found")}, nil
+	}
+	if iface.nat == nil {
+		return &dpdkproto.DeleteNatResponse{Status: errStatus(errors.SNAT_NO_DATA, "nat not found")}, nil
+	}
+	iface.nat = nil
+	return &dpdkproto.DeleteNatResponse{Status: okStatus()}, nil
+}
+
+// ListLocalNats lists the interfaces using the NAT IP, returning their primary IPs like dpservice.
+func (s *Server) ListLocalNats(_ context.Context, req *dpdkproto.ListLocalNatsRequest) (*dpdkproto.ListLocalNatsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := &dpdkproto.ListLocalNatsResponse{Status: okStatus()}
+	for _, id := range sortedKeys(s.interfaces) {
+		iface := s.interfaces[id]
+		if iface.nat == nil || ipString(iface.nat.GetNatIp()) != ipString(req.GetNatIp()) {
+			continue
+		}
+		res.NatEntries = append(res.NatEntries, &dpdkproto.NatEntry{
+			NatIp:   &dpdkproto.IpAddress{Ipver: dpdkproto.IpVersion_IPV4, Address: iface.proto.GetPrimaryIpv4()},
+			MinPort: iface.nat.GetMinPort(),
+			MaxPort: iface.nat.GetMaxPort(),
+			Vni:     iface.proto.GetVni(),
+		})
+	}
+	return res, nil
+}
+
+func neighborNatKey(natIP *dpdkproto.IpAddress, vni, minPort, maxPort uint32) string {
+	return fmt.Sprintf("%s/%d/%d-%d", ipString(natIP), vni, minPort, maxPort)
+}
+
+func (s *Server) CreateNeighborNat(_ context.Context, req *dpdkproto.CreateNeighborNatRequest) (*dpdkproto.CreateNeighborNatResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !validIP(req.GetNatIp()) {
+		return &dpdkproto.CreateNeighborNatResponse{Status: errStatus(errors.BAD_REQUEST, "invalid nat ip")}, nil
+	}
+	key := neighborNatKey(req.GetNatIp(), req.GetVni(), req.GetMinPort(), req.GetMaxPort())
+	if _, ok := s.neighborNats[key]; ok {
+		return &dpdkproto.CreateNeighborNatResponse{Status: errStatus(errors.ALREADY_EXISTS, "neighbor nat already exists")}, nil
+	}
+	s.neighborNats[key] = &dpdkproto.NatEntry{
+		NatIp:         req.GetNatIp(),
+		MinPort:       req.GetMinPort(),
+		MaxPort:       req.GetMaxPort(),
+		UnderlayRoute: req.GetUnderlayRoute(),
+		Vni:           req.GetVni(),
+	}
+	return &dpdkproto.CreateNeighborNatResponse{Status: okStatus()}, nil
+}
+
+func (s *Server) DeleteNeighborNat(_ context.Context, req *dpdkproto.DeleteNeighborNatRequest) (*dpdkproto.DeleteNeighborNatResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := neighborNatKey(req.GetNatIp(), req.GetVni(), req.GetMinPort(), req.GetMaxPort())
+	if _, ok := s.neighborNats[key]; !ok {
+		return &dpdkproto.DeleteNeighborNatResponse{Status: errStatus(errors.NOT_FOUND, "neighbor nat not found")}, nil
+	}
+	delete(s.neighborNats, key)
+	return &dpdkproto.DeleteNeighborNatResponse{Status: okStatus()}, nil
+}
+
+func (s *Server) ListNeighborNats(_ context.Context, req *dpdkproto.ListNeighborNatsRequest) (*dpdkproto.ListNeighborNatsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := &dpdkproto.ListNeighborNatsResponse{Status: okStatus()}
+	for _, key := range sortedKeys(s.neighborNats) {
+		nat := s.neighborNats[key]
+		if ipString(nat.GetNatIp()) == ipString(req.GetNatIp()) {
+			res.NatEntries = append(res.NatEntries, nat)
+		}
+	}
+	return res, nil
+}
+
+func (s *Server) ListRoutes(_ context.Context, req *dpdkproto.ListRoutesRequest) (*dpdkproto.ListRoutesResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := &dpdkproto.ListRoutesResponse{Status: okStatus()}
+	routes := s.routes[req.GetVni()]
+	for _, key := range sortedKeys(routes) {
+		res.Routes = append(res.Routes, routes[key])
+	}
+	return res, nil
+}
+
+func (s *Server) CreateRoute(_ context.Context, req *dpdkproto.CreateRouteRequest) (*dpdkproto.CreateRouteResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vni, route := req.GetVni(), req.GetRoute()
+	if !s.vniInUse(vni) {
+		return &dpdkproto.CreateRouteResponse{Status: errStatus(errors.NO_VNI, "vni %d not in use", vni)}, nil
+	}
+	if !validPrefix(route.GetPrefix()) || !validIP(route.GetNexthopAddress()) {
+		return &dpdkproto.CreateRouteResponse{Status: errStatus(errors.BAD_REQUEST, "invalid route")}, nil
+	}
+	key := prefixKey(route.GetPrefix())
+	if _, ok := s.routes[vni][key]; ok {
+		return &dpdkproto.CreateRouteResponse{Status: errStatus(errors.ROUTE_EXISTS, "route already exists")}, nil
+	}
+	if s.routes[vni] == nil {
+		s.routes[vni] = make(map[string]*dpdkproto.Route)
+	}
+	s.routes[vni][key] = route
+	return &dpdkproto.CreateRouteResponse{Status: okStatus()}, nil
+}
+
+func (s *Server) DeleteRoute(_ context.Context, req *dpdkproto.DeleteRouteRequest) (*dpdkproto.DeleteRouteResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vni := req.GetVni()
+	key := prefixKey(req.GetRoute().GetPrefix())
+	if _, ok := s.routes[vni][key]; !ok {
+		return &dpdkproto.DeleteRouteResponse{Status: errStatus(errors.ROUTE_NOT_FOUND, "route not found")}, nil
+	}
+	delete(s.routes[vni], key)
+	return &dpdkproto.DeleteRouteResponse{Status: okStatus()}, nil
+}
+
+func (s *Server) CheckVniInUse(_ context.Context, req *dpdkproto.CheckVniInUseRequest) (*dpdkproto.CheckVniInUseResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return &dpdkproto.CheckVniInUseResponse{Status: okStatus(), InUse: s.vniInUse(req.GetVni())}, nil
+}
+
+func (s *Server) ResetVni(_ context.Context, req *dpdkproto.ResetVniRequest) (*dpdkproto.ResetVniResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.vniInUse(req.GetVni()) {
+		return &dpdkproto.ResetVniResponse{Status: errStatus(errors.NO_VNI, "vni %d not in use", req.GetVni())}, nil
+	}
+	delete(s.routes, req.GetVni())
+	return &dpdkproto.ResetVniResponse{Status: okStatus()}, nil
+}
+
+func (s *Server) ListFirewallRules(_ context.Context, req *dpdkproto.ListFirewallRulesRequest) (*dpdkproto.ListFirewallRulesResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	iface, ok := s.interfaces[string(req.GetInterfaceId())]
+	if !ok {
+		return &dpdkproto.ListFirewallRulesResponse{Status: errStatus(errors.NO_VM, "interface not found")}, nil
+	}
+	res := &dpdkproto.ListFirewallRulesResponse{Status: okStatus()}
+	for _, id := range sortedKeys(iface.fwRules) {
+		res.Rules = append(res.Rules, iface.fwRules[id])
+	}
+	return res, nil
+}
+
+func (s *Server) CreateFirewallRule(_ context.Context, req *dpdkproto.CreateFirewallRuleRequest) (*dpdkproto.CreateFirewallRuleResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	iface, ok := s.interfaces[string(req.GetInterfaceId())]
+	if !ok {
+		return &dpdkproto.CreateFirewallRuleResponse{Status: errStatus(errors.NO_VM, "interface not found")}, nil
+	}
+	rule := req.GetRule()
+	if !validPrefix(rule.GetSourcePrefix()) || !validPrefix(rule.GetDestinationPrefix()) {
+		return &dpdkproto.CreateFirewallRuleResponse{Status: errStatus(errors.BAD_REQUEST, "invalid firewall rule prefixes")}, nil
+	}
+	id := string(rule.GetId())
+	if id == "" {
+		return &dpdkproto.CreateFirewallRuleResponse{Status: errStatus(errors.BAD_REQUEST, "rule id is required")}, nil
+	}
+	if _, ok := iface.fwRules[id]; ok {
+		return &dpdkproto.CreateFirewallRuleResponse{Status: errStatus(errors.ALREADY_EXISTS, "firewall rule already exists")}, nil
+	}
+	iface.fwRules[id] = rule
+	return &dpdkproto.CreateFirewallRuleResponse{Status: okStatus(), RuleId: rule.GetId()}, nil
+}
+
+func (s *Server) GetFirewallRule(_ context.Context, req *dpdkproto.GetFirewallRuleRequest) (*dpdkproto.GetFirewallRuleResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	iface, ok := s.interfaces[string(req.GetInterfaceId())]
+	if !ok {
+		return &dpdkproto.GetFirewallRuleResponse{Status: errStatus(errors.NO_VM, "interface not found")}, nil
+	}
+	rule, ok := iface.fwRules[string(req.GetRuleId())]
+	if !ok {
+		return &dpdkproto.GetFirewallRuleResponse{Status: errStatus(errors.NOT_FOUND, "firewall rule not found")}, nil
+	}
+	return &dpdkproto.GetFirewallRuleResponse{Status: okStatus(), Rule: rule}, nil
+}
+
+func (s *Server) DeleteFirewallRule(_ context.Context, req *dpdkproto.DeleteFirewallRuleRequest) (*dpdkproto.DeleteFirewallRuleResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	iface, ok := s.interfaces[string(req.GetInterfaceId())]
+	if !ok {
+		return &dpdkproto.DeleteFirewallRuleResponse{Status: errStatus(errors.NO_VM, "interface not found")}, nil
+	}
+	id := string(req.GetRuleId())
+	if _, ok := iface.fwRules[id]; !ok {
+		return &dpdkproto.DeleteFirewallRuleResponse{Status: errStatus(errors.NOT_FOUND, "firewall rule not found")}, nil
+	}
+	delete(iface.fwRules, id)
+	return &dpdkproto.DeleteFirewallRuleResponse{Status: okStatus()}, nil
+}
+
+func (s *Server) CaptureStart(_ context.Context, req *dpdkproto.CaptureStartRequest) (*dpdkproto.CaptureStartResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.capture != nil {
+		return &dpdkproto.CaptureStartResponse{Status: errStatus(errors.ALREADY_ACTIVE, "capture already active")}, nil
+	}
+	s.capture = req.GetCaptureConfig()
+	return &dpdkproto.CaptureStartResponse{Status: okStatus()}, nil
+}
+
+func (s *Server) CaptureStop(_ context.Context, _ *dpdkproto.CaptureStopRequest) (*dpdkproto.CaptureStopResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.capture == nil {
+		return &dpdkproto.CaptureStopResponse{Status: errStatus(errors.NOT_ACTIVE, "capture not active")}, nil
+	}
+	stopped := uint32(len(s.capture.GetInterfaces()))
+	s.capture = nil
+	return &dpdkproto.CaptureStopResponse{Status: okStatus(), StoppedInterfaceCnt: stopped}, nil
+}
+
+func (s *Server) CaptureStatus(_ context.Context, _ *dpdkproto.CaptureStatusRequest) (*dpdkproto.CaptureStatusResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return &dpdkproto.CaptureStatusResponse{Status: okStatus(), IsActive: s.capture != nil, CaptureConfig: s.capture}, nil
+}
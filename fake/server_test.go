@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package fake_test
+
+import (
+	"context"
+	"net/netip"
+
+	"github.com/ironcore-dev/dpservice-cli/fake"
+	"github.com/ironcore-dev/dpservice-go/api"
+	"github.com/ironcore-dev/dpservice-go/client"
+	"github.com/ironcore-dev/dpservice-go/errors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Server", func() {
+	var (
+		ctx = context.Background()
+		c   client.Client
+	)
+
+	BeforeEach(func() {
+		server := fake.NewServer()
+		DeferCleanup(server.Stop)
+
+		var (
+			cleanup func() error
+			err     error
+		)
+		c, cleanup, err = server.NewClient(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(cleanup)
+	})
+
+	createInterface := func(id string, vni uint32, ip string) {
+		ipv4 := netip.MustParseAddr(ip)
+		_, err := c.CreateInterface(ctx, &api.Interface{
+			InterfaceMeta: api.InterfaceMeta{ID: id},
+			Spec:          api.InterfaceSpec{VNI: vni, IPv4: &ipv4},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	expectCode := func(err error, code uint32) {
+		GinkgoHelper()
+		Expect(err).To(HaveOccurred())
+		Expect(errors.IsStatusErrorCode(err, code)).To(BeTrue(), "expected error code %d, got %v", code, err)
+	}
+
+	It("should create, get, list and delete interfaces", func() {
+		createInterface("vm2", 100, "10.0.0.2")
+		createInterface("vm1", 100, "10.0.0.1")
+
+		iface, err := c.GetInterface(ctx, "vm1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(iface.Spec.VNI).To(Equal(uint32(100)))
+		Expect(iface.Spec.IPv4.String()).To(Equal("10.0.0.1"))
+		Expect(iface.Spec.UnderlayRoute).NotTo(BeNil())
+
+		list, err := c.ListInterfaces(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list.Items).To(HaveLen(2))
+		Expect(list.Items[0].ID).To(Equal("vm1"))
+
+		_, err = c.DeleteInterface(ctx, "vm1")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = c.GetInterface(ctx, "vm1")
+		expectCode(err, errors.NOT_FOUND)
+	})
+
+	It("should return the dpservice error codes", func() {
+		createInterface("vm1", 100, "10.0.0.1")
+
+		ipv4 := netip.MustParseAddr("10.0.0.1")
+		_, err := c.CreateInterface(ctx, &api.Interface{
+			InterfaceMeta: api.InterfaceMeta{ID: "vm1"},
+			Spec:          api.InterfaceSpec{VNI: 100, IPv4: &ipv4},
+		})
+		expectCode(err, errors.ALREADY_EXISTS)
+
+		_, err = c.DeleteInterface(ctx, "vm2")
+		expectCode(err, errors.NOT_FOUND)
+
+		prefix := netip.MustParsePrefix("10.0.1.0/24")
+		_, err = c.CreatePrefix(ctx, &api.Prefix{PrefixMeta: api.PrefixMeta{InterfaceID: "vm2"}, Spec: api.PrefixSpec{Prefix: prefix}})
+		expectCode(err, errors.NO_VM)
+		_, err = c.CreatePrefix(ctx, &api.Prefix{PrefixMeta: api.PrefixMeta{InterfaceID: "vm1"}, Spec: api.PrefixSpec{Prefix: prefix}})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = c.CreatePrefix(ctx, &api.Prefix{PrefixMeta: api.PrefixMeta{InterfaceID: "vm1"}, Spec: api.PrefixSpec{Prefix: prefix}})
+		expectCode(err, errors.ROUTE_EXISTS)
+		_, err = c.DeletePrefix(ctx, "vm1", &prefix)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = c.DeletePrefix(ctx, "vm1", &prefix)
+		expectCode(err, errors.ROUTE_NOT_FOUND)
+
+		_, err = c.GetVirtualIP(ctx, "vm1")
+		expectCode(err, errors.SNAT_NO_DATA)
+		vip := netip.MustParseAddr("20.0.0.1")
+		_, err = c.CreateVirtualIP(ctx, &api.VirtualIP{VirtualIPMeta: api.VirtualIPMeta{InterfaceID: "vm1"}, Spec: api.VirtualIPSpec{IP: &vip}})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = c.CreateVirtualIP(ctx, &api.VirtualIP{VirtualIPMeta: api.VirtualIPMeta{InterfaceID: "vm1"}, Spec: api.VirtualIPSpec{IP: &vip}})
+		expectCode(err, errors.SNAT_EXISTS)
+
+		_, err = c.GetLoadBalancer(ctx, "lb1")
+		expectCode(err, errors.NOT_FOUND)
+		target := netip.MustParseAddr("fc00::1")
+		_, err = c.CreateLoadBalancerTarget(ctx, &api.LoadBalancerTarget{
+			LoadBalancerTargetMeta: api.LoadBalancerTargetMeta{LoadbalancerID: "lb1"},
+			Spec:                   api.LoadBalancerTargetSpec{TargetIP: &target},
+		})
+		expectCode(err, errors.NO_LB)
+	})
+
+	It("should manage routes per VNI", func() {
+		prefix := netip.MustParsePrefix("10.0.2.0/24")
+		nextHop := netip.MustParseAddr("fc00::2")
+		route := &api.Route{
+			RouteMeta: api.RouteMeta{VNI: 100},
+			Spec:      api.RouteSpec{Prefix: &prefix, NextHop: &api.RouteNextHop{VNI: 200, IP: &nextHop}},
+		}
+		_, err := c.CreateRoute(ctx, route)
+		expectCode(err, errors.NO_VNI)
+
+		createInterface("vm1", 100, "10.0.0.1")
+		_, err = c.CreateRoute(ctx, route)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = c.CreateRoute(ctx, route)
+		expectCode(err, errors.ROUTE_EXISTS)
+
+		routes, err := c.ListRoutes(ctx, 100)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(routes.Items).To(HaveLen(1))
+		Expect(routes.Items[0].Spec.NextHop.VNI).To(Equal(uint32(200)))
+
+		By("deleting the last interface of the VNI")
+		_, err = c.DeleteInterface(ctx, "vm1")
+		Expect(err).NotTo(HaveOccurred())
+		routes, err = c.ListRoutes(ctx, 100)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(routes.Items).To(BeEmpty())
+	})
+
+	It("should manage NATs and firewall rules of interfaces", func() {
+		createInterface("vm1", 100, "10.0.0.1")
+
+		natIP := netip.MustParseAddr("20.0.0.2")
+		_, err := c.CreateNat(ctx, &api.Nat{NatMeta: api.NatMeta{InterfaceID: "vm1"}, Spec: api.NatSpec{NatIP: &natIP, MinPort: 100, MaxPort: 200}})
+		Expect(err).NotTo(HaveOccurred())
+		nats, err := c.ListLocalNats(ctx, &natIP)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nats.Items).To(HaveLen(1))
+		Expect(nats.Items[0].Spec.MinPort).To(Equal(uint32(100)))
+
+		src, dst := netip.MustParsePrefix("0.0.0.0/0"), netip.MustParsePrefix("10.0.0.1/32")
+		rule := &api.FirewallRule{
+			FirewallRuleMeta: api.FirewallRuleMeta{InterfaceID: "vm1"},
+			Spec: api.FirewallRuleSpec{
+				RuleID:            "fr1",
+				TrafficDirection:  "Ingress",
+				FirewallAction:    "Accept",
+				Priority:          1000,
+				SourcePrefix:      &src,
+				DestinationPrefix: &dst,
+			},
+		}
+		_, err = c.CreateFirewallRule(ctx, rule)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = c.CreateFirewallRule(ctx, rule)
+		expectCode(err, errors.ALREADY_EXISTS)
+
+		got, err := c.GetFirewallRule(ctx, "vm1", "fr1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got.Spec.Priority).To(Equal(uint32(1000)))
+		_, err = c.DeleteFirewallRule(ctx, "vm1", "fr2")
+		expectCode(err, errors.NOT_FOUND)
+	})
+})
@@ -129,6 +129,10 @@ func (s *DirSource) Next() (ReadCloserExt, error) {
 }
 
 func IterateObjects(iterator *Iterator, scheme *runtime.Scheme, f func(obj any) error) error {
+	return iterateObjects(iterator, scheme, nil, f)
+}
+
+func iterateObjects(iterator *Iterator, scheme *runtime.Scheme, rawSpecs runtime.RawSpecs, f func(obj any) error) error {
 	for {
 		src, err := iterator.Next()
 		if err != nil {
@@ -153,6 +157,7 @@ func IterateObjects(iterator *Iterator, scheme *runtime.Scheme, f func(obj any)
 			}
 
 			decoder := runtime.NewKindDecoder(runtime.DefaultScheme, runtime.NewPeekDecoder(rce, newDecoder))
+			decoder.RecordRawSpecs(rawSpecs)
 			for {
 				obj, err := decoder.Next()
 				if err != nil {
@@ -181,3 +186,17 @@ func CollectObjects(iterator *Iterator, scheme *runtime.Scheme) ([]any, error) {
 	}
 	return objs, nil
 }
+
+// CollectObjectsWithRawSpecs is like CollectObjects, but also returns the spec of every object
+// as read from the sources.
+func CollectObjectsWithRawSpecs(iterator *Iterator, scheme *runtime.Scheme) ([]any, runtime.RawSpecs, error) {
+	var objs []any
+	rawSpecs := make(runtime.RawSpecs)
+	if err := iterateObjects(iterator, scheme, rawSpecs, func(obj any) error {
+		objs = append(objs, obj)
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+	return objs, rawSpecs, nil
+}